@@ -0,0 +1,141 @@
+package bob
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/benchkram/errz"
+
+	"github.com/benchkram/bob/bob/bobfile"
+	"github.com/benchkram/bob/bob/global"
+	"github.com/benchkram/bob/bobgit"
+	"github.com/benchkram/bob/pkg/file"
+	"github.com/benchkram/bob/pkg/filepathutil"
+	"github.com/benchkram/bob/pkg/usererror"
+)
+
+// Affected describes the tasks impacted by changes since a git revision.
+type Affected struct {
+	// Files changed since the revision.
+	Files []string `json:"files"`
+
+	// Tasks are the build tasks with changed inputs and
+	// the build tasks depending on them.
+	Tasks []string `json:"tasks"`
+
+	// Runs are the run tasks depending on affected tasks.
+	Runs []string `json:"runs"`
+}
+
+// Affected determines the tasks impacted by files changed between the revision
+// since and the worktree of all repositories of the workspace. A task is affected
+// when one of its inputs or its bobfile changed, or when it depends on an affected task.
+func (b *B) Affected(since string) (_ *Affected, err error) {
+	defer errz.Recover(&err)
+
+	ag, err := b.Aggregate()
+	errz.Fatal(err)
+
+	files, err := bobgit.Changed(b.dir, since)
+	if err != nil {
+		if errors.Is(err, bobgit.ErrRevisionNotFound) || errors.Is(err, bobgit.ErrCouldNotFindGitDir) {
+			return nil, usererror.Wrap(err)
+		}
+		errz.Fatal(err)
+	}
+
+	affected := &Affected{Files: []string{}, Tasks: []string{}, Runs: []string{}}
+	for _, f := range files {
+		rel, err := filepath.Rel(b.dir, f)
+		errz.Fatal(err)
+		affected.Files = append(affected.Files, rel)
+	}
+
+	changed, err := changedTasks(ag, files)
+	errz.Fatal(err)
+
+	for _, taskname := range dependentTasks(ag, changed) {
+		if _, ok := ag.BTasks[taskname]; ok {
+			affected.Tasks = append(affected.Tasks, taskname)
+		} else {
+			affected.Runs = append(affected.Runs, taskname)
+		}
+	}
+
+	return affected, nil
+}
+
+// changedTasks returns the build tasks of which an input or the bobfile is one of files.
+// Deleted files are matched against the input patterns of a task.
+func changedTasks(ag *bobfile.Bobfile, files []string) (_ []string, err error) {
+	defer errz.Recover(&err)
+
+	var changed []string
+	for taskname, task := range ag.BTasks {
+		dir, err := filepath.Abs(task.Dir())
+		errz.Fatal(err)
+		bobfilePath := filepath.Join(dir, global.BobFileName)
+
+		inputs := make(map[string]bool, len(task.Inputs()))
+		for _, input := range task.Inputs() {
+			inputs[input] = true
+		}
+
+		for _, f := range files {
+			isInput := inputs[f] || f == bobfilePath
+			if !isInput && !file.Exists(f) {
+				isInput, err = filepathutil.Match(dir, task.InputPatterns(), f)
+				if err != nil {
+					errz.Fatal(fmt.Errorf("failed to match inputs of task %s: %w", taskname, err))
+				}
+			}
+			if isInput {
+				changed = append(changed, taskname)
+				break
+			}
+		}
+	}
+
+	return changed, nil
+}
+
+// dependentTasks returns the given tasks and all build
+// and run tasks transitively depending on them, sorted.
+func dependentTasks(ag *bobfile.Bobfile, tasknames []string) []string {
+	dependents := make(map[string][]string)
+	for taskname, task := range ag.BTasks {
+		for _, d := range task.DependsOn {
+			dependents[d] = append(dependents[d], taskname)
+		}
+	}
+	for runname, run := range ag.RTasks {
+		for _, d := range run.DependsOn {
+			dependents[d] = append(dependents[d], runname)
+		}
+	}
+
+	visited := make(map[string]bool)
+	var visit func(taskname string)
+	visit = func(taskname string) {
+		if visited[taskname] {
+			return
+		}
+		visited[taskname] = true
+		for _, d := range dependents[taskname] {
+			visit(d)
+		}
+	}
+	for _, taskname := range tasknames {
+		visit(taskname)
+	}
+
+	result := make([]string, 0, len(visited))
+	for taskname := range visited {
+		result = append(result, taskname)
+	}
+	sort.Strings(result)
+
+	return result
+}
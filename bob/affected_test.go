@@ -0,0 +1,103 @@
+package bob
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benchkram/bob/pkg/cmdutil"
+)
+
+func TestAffected(t *testing.T) {
+	// allows to commit without a configured git identity
+	t.Setenv("GIT_AUTHOR_NAME", "bob")
+	t.Setenv("GIT_AUTHOR_EMAIL", "bob@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "bob")
+	t.Setenv("GIT_COMMITTER_EMAIL", "bob@example.com")
+
+	dir := t.TempDir()
+	dir, err := filepath.EvalSymlinks(dir)
+	require.Nil(t, err)
+
+	write := func(path, content string) {
+		path = filepath.Join(dir, path)
+		require.Nil(t, os.MkdirAll(filepath.Dir(path), 0775))
+		require.Nil(t, os.WriteFile(path, []byte(content), 0664))
+	}
+
+	write("bob.yaml", `
+import: [second]
+build:
+  app:
+    input: main.go
+    cmd: echo app
+    dependsOn: [second/lib]
+  docs:
+    input: docs/
+    cmd: echo docs
+run:
+  server:
+    type: binary
+    path: ./app
+    dependsOn: [app]
+`)
+	write("main.go", "package main")
+	write("docs/readme.md", "readme")
+	write("second/bob.yaml", `
+build:
+  lib:
+    input: "*.go"
+    cmd: echo lib
+  other:
+    input: other.txt
+    cmd: echo other
+`)
+	write("second/lib.go", "package lib")
+	write("second/other.txt", "other")
+
+	require.Nil(t, cmdutil.RunGit(dir, "init"))
+	require.Nil(t, cmdutil.RunGit(dir, "add", "-A"))
+	require.Nil(t, cmdutil.RunGit(dir, "commit", "-m", "initial"))
+
+	chdir(t, dir)
+
+	b, err := BobWithBaseStoreDir(t.TempDir(), WithDir(dir))
+	require.Nil(t, err)
+
+	affected, err := b.Affected("HEAD")
+	require.Nil(t, err)
+	assert.Equal(t, &Affected{Files: []string{}, Tasks: []string{}, Runs: []string{}}, affected)
+
+	// a changed input affects all dependent tasks
+	write("second/lib.go", "package lib // changed")
+	affected, err = b.Affected("HEAD")
+	require.Nil(t, err)
+	assert.Equal(t, []string{"second/lib.go"}, affected.Files)
+	assert.Equal(t, []string{"app", "second/lib"}, affected.Tasks)
+	assert.Equal(t, []string{"server"}, affected.Runs)
+
+	// deleted inputs and changed bobfiles
+	require.Nil(t, cmdutil.RunGit(dir, "commit", "-am", "change lib"))
+	require.Nil(t, os.Remove(filepath.Join(dir, "docs", "readme.md")))
+	write("second/bob.yaml", `
+build:
+  lib:
+    input: "*.go"
+    cmd: echo lib
+  other:
+    input: other.txt
+    cmd: echo other changed
+`)
+	affected, err = b.Affected("HEAD")
+	require.Nil(t, err)
+	assert.Equal(t, []string{"docs/readme.md", "second/bob.yaml"}, affected.Files)
+	assert.Equal(t, []string{"app", "docs", "second/lib", "second/other"}, affected.Tasks)
+
+	// changes of previous commits
+	affected, err = b.Affected("HEAD~1")
+	require.Nil(t, err)
+	assert.Equal(t, []string{"docs/readme.md", "second/bob.yaml", "second/lib.go"}, affected.Files)
+}
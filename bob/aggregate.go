@@ -33,12 +33,12 @@ func (b *B) PrintVersionCompatibility(bobfile *bobfile.Bobfile) {
 			bobletVersion, _ := version.NewVersion(boblet.Version)
 
 			if binVersion.Core().Segments64()[0] != bobletVersion.Core().Segments64()[0] {
-				fmt.Println(aurora.Red(fmt.Sprintf("Warning: major version mismatch: Your bobfile's major version (%s, '%s') is different from the CLI version (%s). This might lead to unexpected errors.", boblet.Version, boblet.Dir(), binVersion)).String())
+				fmt.Fprintln(os.Stderr, aurora.Red(fmt.Sprintf("Warning: major version mismatch: Your bobfile's major version (%s, '%s') is different from the CLI version (%s). This might lead to unexpected errors.", boblet.Version, boblet.Dir(), binVersion)).String())
 				continue
 			}
 
 			if binVersion.LessThan(bobletVersion) {
-				fmt.Println(aurora.Red(fmt.Sprintf("Warning: possible version incompatibility: Your bobfile's version (%s, '%s') is higher than the CLI version (%s). Some features might not work as expected.", boblet.Version, boblet.Dir(), binVersion)).String())
+				fmt.Fprintln(os.Stderr, aurora.Red(fmt.Sprintf("Warning: possible version incompatibility: Your bobfile's version (%s, '%s') is higher than the CLI version (%s). Some features might not work as expected.", boblet.Version, boblet.Dir(), binVersion)).String())
 				continue
 			}
 		}
@@ -74,6 +74,9 @@ func (b *B) AggregateSparse(omitRunTasks ...bool) (aggregate *bobfile.Bobfile, e
 	bobs, err := readImports(aggregate, true)
 	errz.Fatal(err)
 
+	err = expandMatrices(append(bobs, aggregate))
+	errz.Fatal(err)
+
 	if aggregate.Project == "" {
 		// TODO: maybe don't leak absolute path of environment
 
@@ -123,14 +126,24 @@ func (b *B) Aggregate() (aggregate *bobfile.Bobfile, err error) {
 	bobs, err := readImports(aggregate, false)
 	errz.Fatal(err)
 
+	err = expandMatrices(append(bobs, aggregate))
+	errz.Fatal(err)
+
 	for _, boblet := range append(bobs, aggregate) {
 		for key, task := range boblet.BTasks {
-			task.SetEnv(envutil.Merge(boblet.Vars(), b.env))
+			// Matrix values take precedence to
+			// assure each instance gets its own.
+			env, passed := b.taskEnv(boblet.Vars(), task.EnvDirty, task.PassEnv)
+			task.SetEnv(envutil.Merge(env, task.MatrixEnv()))
+			task.SetPassedEnv(envutil.Without(passed, task.MatrixEnv()))
+			task.SetUseGitignore(boblet.UseGitignore)
+			task.SetVars(boblet.Variables)
 			boblet.BTasks[key] = task
 		}
 
 		for key, task := range boblet.RTasks {
-			task.SetEnv(envutil.Merge(boblet.Vars(), b.env))
+			env, _ := b.taskEnv(boblet.Vars(), task.EnvDirty, task.PassEnv)
+			task.SetEnv(env)
 			boblet.RTasks[key] = task
 		}
 	}
@@ -153,10 +166,18 @@ func (b *B) Aggregate() (aggregate *bobfile.Bobfile, err error) {
 	// Merge runs into one Bobfile
 	aggregate = b.addRunTasksToAggregate(aggregate, bobs)
 
+	// Following dependencies must terminate.
+	err = verifyNoCycles(aggregate, decorations)
+	errz.Fatal(err)
+
+	logStore := b.projectLogStore()
+
 	// Assure tasks are correctly initialised.
 	for i, task := range aggregate.BTasks {
 		task.WithLocalstore(b.local)
 		task.WithBuildinfoStore(b.buildInfoStore)
+		task.WithHashCache(b.hashCache)
+		task.WithLogStore(logStore)
 		task.WithDockerRegistryClient(b.dockerRegistryClient)
 
 		// a task must always-rebuild when caching is disabled
@@ -184,6 +205,10 @@ func (b *B) Aggregate() (aggregate *bobfile.Bobfile, err error) {
 	aggregate.Dependencies = make([]string, 0)
 	aggregate.Dependencies = append(aggregate.Dependencies, allDeps...)
 
+	// Aggregate all resource pools
+	aggregate.Resources, err = mergeResources(append(bobs, aggregate))
+	errz.Fatal(err)
+
 	// Initialize remote store in case of a valid remote url / project name
 	if aggregate.Project != "" {
 		projectName, err := project.Parse(aggregate.Project)
@@ -204,7 +229,7 @@ func (b *B) Aggregate() (aggregate *bobfile.Bobfile, err error) {
 			authCtx, err := b.CurrentAuthContext()
 			if err != nil {
 				if errors.Is(err, auth.ErrNotFound) {
-					fmt.Printf("Will not sync to %s because of missing auth context\n", projectName)
+					fmt.Fprintf(os.Stderr, "Will not sync to %s because of missing auth context\n", projectName)
 				} else {
 					return nil, err
 				}
@@ -229,6 +254,33 @@ func (b *B) Aggregate() (aggregate *bobfile.Bobfile, err error) {
 	return aggregate, nil
 }
 
+// taskEnv combines the environment of a build or run task.
+// Later sources take precedence: bobfile variables, the task's env,
+// host variables listed in passEnv, `--env` flags.
+// Also returns the variables which got their value from the host.
+func (b *B) taskEnv(vars []string, env map[string]string, passEnv []string) (_ []string, passed []string) {
+	result := envutil.Merge(vars, envutil.FromMap(env))
+	host := envutil.Lookup(passEnv)
+	result = envutil.Merge(result, host)
+	return envutil.Merge(result, b.env), envutil.Without(host, b.env)
+}
+
+// mergeResources collects the resource pools of all bobfiles.
+// A pool declared in multiple bobfiles must have the same capacity.
+func mergeResources(bobs []*bobfile.Bobfile) (map[string]int, error) {
+	resources := make(map[string]int)
+	for _, bf := range bobs {
+		for name, capacity := range bf.Resources {
+			if c, ok := resources[name]; ok && c != capacity {
+				return nil, usererror.Wrap(fmt.Errorf("%w: resource `%s` declared with different capacities (%d, %d)",
+					bobfile.ErrInvalidResource, name, c, capacity))
+			}
+			resources[name] = capacity
+		}
+	}
+	return resources, nil
+}
+
 func addTaskPrefix(prefix, taskname string) string {
 	taskname = filepath.Join(prefix, taskname)
 	taskname = strings.TrimPrefix(taskname, string(bobtask.TaskPathSeparator))
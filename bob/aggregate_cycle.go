@@ -0,0 +1,110 @@
+package bob
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/benchkram/bob/bob/bobfile"
+	"github.com/benchkram/bob/bob/global"
+	"github.com/benchkram/bob/pkg/usererror"
+)
+
+// verifyNoCycles assures the dependencies of build and run tasks don't form a cycle.
+// The returned error contains the tasks of the first cycle found, each edge of the
+// cycle annotated with the bobfile declaring it.
+//
+// decorations maps a decorated task to the tasks added by the top-level bobfile.
+func verifyNoCycles(ag *bobfile.Bobfile, decorations map[string][]string) error {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	dependsOn := func(taskname string) []string {
+		if task, ok := ag.BTasks[taskname]; ok {
+			return task.DependsOn
+		}
+		if run, ok := ag.RTasks[taskname]; ok {
+			return run.DependsOn
+		}
+		return nil
+	}
+
+	state := make(map[string]int)
+	var stack []string
+	var cycle []string
+
+	var visit func(taskname string) bool
+	visit = func(taskname string) bool {
+		switch state[taskname] {
+		case visited:
+			return false
+		case visiting:
+			for i, name := range stack {
+				if name == taskname {
+					cycle = append(append(cycle, stack[i:]...), taskname)
+					break
+				}
+			}
+			return true
+		}
+
+		state[taskname] = visiting
+		stack = append(stack, taskname)
+		for _, d := range dependsOn(taskname) {
+			if visit(d) {
+				return true
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[taskname] = visited
+
+		return false
+	}
+
+	// sorted for a deterministic report
+	tasknames := make([]string, 0, len(ag.BTasks)+len(ag.RTasks))
+	for taskname := range ag.BTasks {
+		tasknames = append(tasknames, taskname)
+	}
+	for taskname := range ag.RTasks {
+		tasknames = append(tasknames, taskname)
+	}
+	sort.Strings(tasknames)
+
+	for _, taskname := range tasknames {
+		if !visit(taskname) {
+			continue
+		}
+
+		var edges []string
+		for i := 0; i < len(cycle)-1; i++ {
+			from, to := cycle[i], cycle[i+1]
+			edges = append(edges, fmt.Sprintf("  %s -> %s (%s)", from, to, dependencyBobfile(ag, decorations, from, to)))
+		}
+		return usererror.Wrap(fmt.Errorf("%w: %s", ErrCircularDependency, strings.Join(cycle, " -> "))).
+			WithSummary(strings.Join(edges, "\n"))
+	}
+
+	return nil
+}
+
+// dependencyBobfile returns the path of the bobfile declaring that task from depends on task to.
+func dependencyBobfile(ag *bobfile.Bobfile, decorations map[string][]string, from, to string) string {
+	for _, d := range decorations[from] {
+		if d == to {
+			return global.BobFileName
+		}
+	}
+
+	var dir string
+	if task, ok := ag.BTasks[from]; ok {
+		dir = task.Dir()
+	} else if run, ok := ag.RTasks[from]; ok {
+		dir = run.Dir()
+	}
+	return filepath.Join(dir, global.BobFileName)
+}
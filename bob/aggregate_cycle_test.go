@@ -0,0 +1,151 @@
+package bob
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/benchkram/bob/pkg/usererror"
+)
+
+// bobWithBobfiles writes the bobfiles to a temporary directory
+// and returns a bob using it as working directory.
+func bobWithBobfiles(t *testing.T, bobfiles map[string]string, opts ...Option) *B {
+	dir := t.TempDir()
+	for path, content := range bobfiles {
+		path = filepath.Join(dir, path)
+		assert.Nil(t, os.MkdirAll(filepath.Dir(path), 0775))
+		assert.Nil(t, os.WriteFile(path, []byte(content), 0664))
+	}
+
+	chdir(t, dir)
+
+	b, err := BobWithBaseStoreDir(t.TempDir(), append([]Option{WithDir(dir)}, opts...)...)
+	assert.Nil(t, err)
+
+	return b
+}
+
+// chdir changes the working directory to dir
+// and restores it after the test.
+func chdir(t testing.TB, dir string) {
+	wd, err := os.Getwd()
+	assert.Nil(t, err)
+	assert.Nil(t, os.Chdir(dir))
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+}
+
+func aggregateBobfiles(t *testing.T, bobfiles map[string]string) error {
+	_, err := bobWithBobfiles(t, bobfiles).Aggregate()
+	return err
+}
+
+func TestAggregateDetectsCycles(t *testing.T) {
+	tests := []struct {
+		name     string
+		bobfiles map[string]string
+		cycle    string
+		summary  string
+	}{
+		{
+			name: "imported bobfile",
+			bobfiles: map[string]string{
+				"bob.yaml": `
+import: [second]
+build:
+  a:
+    cmd: echo a
+    dependsOn: [second/b]
+`,
+				"second/bob.yaml": `
+build:
+  b:
+    cmd: echo b
+    dependsOn: [c]
+  c:
+    cmd: echo c
+    dependsOn: [b]
+`,
+			},
+			cycle:   "second/b -> second/c -> second/b",
+			summary: "  second/b -> second/c (second/bob.yaml)\n  second/c -> second/b (second/bob.yaml)",
+		},
+		{
+			name: "decorated imported task",
+			bobfiles: map[string]string{
+				"bob.yaml": `
+import: [second]
+build:
+  a:
+    cmd: echo a
+    dependsOn: [second/b]
+  second/c:
+    dependsOn: [a]
+`,
+				"second/bob.yaml": `
+build:
+  b:
+    cmd: echo b
+    dependsOn: [c]
+  c:
+    cmd: echo c
+`,
+			},
+			cycle: "a -> second/b -> second/c -> a",
+			summary: "  a -> second/b (bob.yaml)\n" +
+				"  second/b -> second/c (second/bob.yaml)\n" +
+				"  second/c -> a (bob.yaml)",
+		},
+		{
+			name: "run tasks",
+			bobfiles: map[string]string{
+				"bob.yaml": `
+build:
+  build:
+    cmd: echo build
+run:
+  server:
+    type: binary
+    path: ./server
+    dependsOn: [build, database]
+  database:
+    type: binary
+    path: ./database
+    dependsOn: [server]
+`,
+			},
+			cycle:   "database -> server -> database",
+			summary: "  database -> server (bob.yaml)\n  server -> database (bob.yaml)",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := aggregateBobfiles(t, test.bobfiles)
+			assert.ErrorIs(t, err, ErrCircularDependency)
+			assert.Contains(t, err.Error(), test.cycle)
+
+			var uerr *usererror.E
+			assert.True(t, errors.As(err, &uerr))
+			assert.Equal(t, test.summary, uerr.Summary())
+		})
+	}
+
+	err := aggregateBobfiles(t, map[string]string{
+		"bob.yaml": `
+build:
+  a:
+    cmd: echo a
+    dependsOn: [b, c]
+  b:
+    cmd: echo b
+    dependsOn: [c]
+  c:
+    cmd: echo c
+`,
+	})
+	assert.Nil(t, err)
+}
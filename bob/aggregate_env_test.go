@@ -0,0 +1,122 @@
+package bob
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/benchkram/bob/pkg/sliceutil"
+)
+
+var withTaskEnv = map[string]string{
+	"bob.yaml": `
+variables:
+  A: variable
+  B: variable
+  C: variable
+  D: variable
+build:
+  build:
+    cmd: go build
+    env:
+      B: task
+      D: task
+    passEnv: [C, SSH_AUTH_SOCK]
+  cross:
+    cmd: go build
+    env:
+      GOOS: task
+    matrix:
+      GOOS: [linux]
+run:
+  server:
+    type: binary
+    path: ./server
+    env:
+      B: run
+    passEnv: [SSH_AUTH_SOCK]
+`,
+}
+
+func TestAggregateTaskEnv(t *testing.T) {
+	t.Setenv("C", "host")
+	t.Setenv("SSH_AUTH_SOCK", "/tmp/agent.sock")
+	t.Setenv("B", "host, not passed")
+
+	b := bobWithBobfiles(t, withTaskEnv, WithEnvVariables([]string{"D=flag", "GOOS=flag"}))
+	ag, err := b.Aggregate()
+	assert.Nil(t, err)
+
+	// bobfile variables < env < passEnv < --env < matrix
+	build := ag.BTasks["build"]
+	cross := ag.BTasks["cross[linux]"]
+	for _, e := range []string{"A=variable", "B=task", "C=host", "D=flag", "SSH_AUTH_SOCK=/tmp/agent.sock"} {
+		assert.True(t, sliceutil.Contains(build.Env(), e), e)
+	}
+	assert.True(t, sliceutil.Contains(cross.Env(), "GOOS=linux"))
+
+	server := ag.RTasks["server"]
+	for _, e := range []string{"A=variable", "B=run", "D=flag", "SSH_AUTH_SOCK=/tmp/agent.sock"} {
+		assert.True(t, sliceutil.Contains(server.Env(), e), e)
+	}
+	assert.False(t, sliceutil.Contains(server.Env(), "C=host"), "C is not passed to the run")
+}
+
+func TestTaskEnvInputHash(t *testing.T) {
+	t.Setenv("C", "host")
+	t.Setenv("SSH_AUTH_SOCK", "/tmp/agent.sock")
+
+	b := bobWithBobfiles(t, withTaskEnv)
+	hash := func() string {
+		ag, err := b.Aggregate()
+		assert.Nil(t, err)
+		task := ag.BTasks["build"]
+		h, err := task.HashIn()
+		assert.Nil(t, err)
+		return h.String()
+	}
+	initial := hash()
+
+	// values of passed variables are not part of the hash
+	t.Setenv("C", "other host")
+	t.Setenv("SSH_AUTH_SOCK", "/tmp/other-agent.sock")
+	assert.Equal(t, initial, hash())
+
+	// values of the task's env are
+	bobfile := strings.Replace(withTaskEnv["bob.yaml"], "B: task", "B: changed", 1)
+	assert.Nil(t, os.WriteFile("bob.yaml", []byte(bobfile), 0664))
+	changed := hash()
+	assert.NotEqual(t, initial, changed)
+
+	// a passed variable unset on the host keeps the
+	// value of the bobfile variable, which is hashed
+	assert.Nil(t, os.Unsetenv("C"))
+	unset := hash()
+	assert.NotEqual(t, changed, unset)
+
+	bobfile = strings.Replace(bobfile, "C: variable", "C: changed", 1)
+	assert.Nil(t, os.WriteFile("bob.yaml", []byte(bobfile), 0664))
+	assert.NotEqual(t, unset, hash())
+}
+
+func TestTaskEnvInputHashEnvFlag(t *testing.T) {
+	t.Setenv("C", "host")
+
+	dir := bobWithBobfiles(t, withTaskEnv).dir
+	hash := func(flags []string) string {
+		b, err := BobWithBaseStoreDir(t.TempDir(), WithDir(dir), WithEnvVariables(flags))
+		assert.Nil(t, err)
+		ag, err := b.Aggregate()
+		assert.Nil(t, err)
+		task := ag.BTasks["build"]
+		h, err := task.HashIn()
+		assert.Nil(t, err)
+		return h.String()
+	}
+
+	// a passed variable set by `--env` is hashed
+	assert.Equal(t, hash([]string{"C=one"}), hash([]string{"C=one"}))
+	assert.NotEqual(t, hash([]string{"C=one"}), hash([]string{"C=two"}))
+}
@@ -0,0 +1,79 @@
+package bob
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/benchkram/bob/pkg/sliceutil"
+)
+
+func TestAggregateExpandsMatrix(t *testing.T) {
+	b := bobWithBobfiles(t, map[string]string{
+		"bob.yaml": `
+import: [second]
+build:
+  release:
+    cmd: echo release
+    dependsOn: [second/build]
+  second/build:
+    dependsOn: [lint]
+  lint:
+    cmd: echo lint
+run:
+  server:
+    type: binary
+    path: ./server
+    dependsOn: [second/build]
+`,
+		"second/bob.yaml": `
+variables:
+  GOOS: plan9
+build:
+  build:
+    cmd: go build -o app-${GOOS}-${GOARCH}
+    target: app-${GOOS}-${GOARCH}
+    matrix:
+      GOOS: [linux, darwin]
+      GOARCH: [amd64]
+  package:
+    cmd: tar -czf app.tar.gz app-*
+    dependsOn: [build]
+`,
+	})
+
+	ag, err := b.Aggregate()
+	assert.Nil(t, err)
+
+	instances := []string{"second/build[linux,amd64]", "second/build[darwin,amd64]"}
+	assert.Equal(t, []string{
+		"lint",
+		"release",
+		"second/build[darwin,amd64]",
+		"second/build[linux,amd64]",
+		"second/package",
+	}, ag.BTasks.KeysSortedAlpabethically())
+
+	assert.Equal(t, instances, ag.BTasks["release"].DependsOn)
+	assert.Equal(t, instances, ag.BTasks["second/package"].DependsOn)
+	assert.Equal(t, instances, ag.RTasks["server"].DependsOn)
+
+	// the decoration applies to all instances
+	assert.Equal(t, []string{"lint"}, ag.BTasks["second/build[linux,amd64]"].DependsOn)
+
+	linux := ag.BTasks["second/build[linux,amd64]"]
+	darwin := ag.BTasks["second/build[darwin,amd64]"]
+
+	// matrix values take precedence over variables
+	assert.True(t, sliceutil.Contains(linux.Env(), "GOOS=linux"))
+	assert.False(t, sliceutil.Contains(linux.Env(), "GOOS=plan9"))
+
+	linuxTargets, _ := linux.DeclaredTargets()
+	assert.Equal(t, []string{"second/app-linux-amd64"}, linuxTargets)
+
+	linuxHash, err := linux.HashIn()
+	assert.Nil(t, err)
+	darwinHash, err := darwin.HashIn()
+	assert.Nil(t, err)
+	assert.NotEqual(t, linuxHash, darwinHash)
+}
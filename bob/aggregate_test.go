@@ -25,8 +25,7 @@ func BenchmarkAggregateOnPlayground(b *testing.B) {
 
 	defer os.RemoveAll(dir)
 
-	err = os.Chdir(dir)
-	assert.Nil(b, err)
+	chdir(b, dir)
 
 	testBob, err := Bob(WithDir(dir))
 	assert.Nil(b, err)
@@ -70,8 +69,7 @@ func benchmarkAggregate(b *testing.B, ignoredMultiplier int) {
 
 	defer os.RemoveAll(dir)
 
-	err = os.Chdir(dir)
-	assert.Nil(b, err)
+	chdir(b, dir)
 
 	testBob, err := Bob(WithDir(dir))
 	assert.Nil(b, err)
@@ -187,8 +185,7 @@ func TestEmptyProjectName(t *testing.T) {
 
 	defer os.RemoveAll(dir)
 
-	err = os.Chdir(dir)
-	assert.Nil(t, err)
+	chdir(t, dir)
 
 	testBob, err := Bob(WithDir(dir))
 	assert.Nil(t, err)
@@ -210,8 +207,7 @@ func TestProjectName(t *testing.T) {
 
 	defer os.RemoveAll(dir)
 
-	err = os.Chdir(dir)
-	assert.Nil(t, err)
+	chdir(t, dir)
 
 	testBob, err := Bob(WithDir(dir))
 	assert.Nil(t, err)
@@ -237,8 +233,7 @@ func TestInvalidProjectName(t *testing.T) {
 
 	defer os.RemoveAll(dir)
 
-	err = os.Chdir(dir)
-	assert.Nil(t, err)
+	chdir(t, dir)
 
 	testBob, err := Bob(WithDir(dir))
 	assert.Nil(t, err)
@@ -330,8 +325,7 @@ func TestMultiLevelBobfileSameProjectName(t *testing.T) {
 
 	defer os.RemoveAll(dir)
 
-	err = os.Chdir(dir)
-	assert.Nil(t, err)
+	chdir(t, dir)
 
 	testBob, err := Bob(WithDir(dir))
 	assert.Nil(t, err)
@@ -7,6 +7,8 @@ import (
 	"strings"
 
 	"github.com/benchkram/bob/bob/bobfile"
+	"github.com/benchkram/bob/bob/global"
+	"github.com/benchkram/bob/bobtask"
 	"github.com/benchkram/bob/pkg/usererror"
 	"github.com/benchkram/errz"
 )
@@ -30,6 +32,17 @@ func syncProjectName(a *bobfile.Bobfile, bobs []*bobfile.Bobfile) (*bobfile.Bobf
 	return a, bobs
 }
 
+// expandMatrices replaces the matrix tasks of each bobfile by their instances.
+func expandMatrices(bobs []*bobfile.Bobfile) error {
+	for _, bf := range bobs {
+		err := bf.BTasks.ExpandMatrices()
+		if err != nil {
+			return usererror.Wrapm(err, fmt.Sprintf("[bobfile:%s]", filepath.Join(bf.Dir(), global.BobFileName)))
+		}
+	}
+	return nil
+}
+
 func (b *B) addBuildTasksToAggregate(a *bobfile.Bobfile, bobs []*bobfile.Bobfile, decorations map[string][]string) (*bobfile.Bobfile, error) {
 	allTasks := make(map[string]bool)
 
@@ -46,13 +59,15 @@ func (b *B) addBuildTasksToAggregate(a *bobfile.Bobfile, bobs []*bobfile.Bobfile
 			prefix := strings.TrimPrefix(dir, b.dir)
 			taskname := addTaskPrefix(prefix, taskname)
 
-			allTasks[taskname] = true
 			// Alter the taskname.
 			task.SetName(taskname)
+			allTasks[taskname] = true
+			// A decoration of a matrix task applies to all its instances.
+			allTasks[task.MatrixName()] = true
 
 			// Rewrite dependent tasks to global scope.
 			var dependsOn []string
-			if dependsFromDecoration, ok := decorations[taskname]; ok {
+			if dependsFromDecoration, ok := decorations[task.MatrixName()]; ok {
 				dependsOn = append(dependsOn, dependsFromDecoration...)
 			}
 			for _, dependentTask := range task.DependsOn {
@@ -71,6 +86,17 @@ func (b *B) addBuildTasksToAggregate(a *bobfile.Bobfile, bobs []*bobfile.Bobfile
 		}
 	}
 
+	// Depending on a matrix task means depending on all its instances.
+	matrices := a.BTasks.Matrices()
+	for name := range matrices {
+		// decorations of a matrix task are merged into its instances
+		delete(a.BTasks, name)
+	}
+	for name, task := range a.BTasks {
+		task.DependsOn = bobtask.ExpandMatrixNames(task.DependsOn, matrices)
+		a.BTasks[name] = task
+	}
+
 	return a, nil
 }
 
@@ -107,6 +133,12 @@ func (b *B) addRunTasksToAggregate(
 		}
 	}
 
+	matrices := a.BTasks.Matrices()
+	for name, run := range a.RTasks {
+		run.DependsOn = bobtask.ExpandMatrixNames(run.DependsOn, matrices)
+		a.RTasks[name] = run
+	}
+
 	return a
 }
 
@@ -6,8 +6,13 @@ import (
 	"runtime"
 
 	"github.com/benchkram/bob/bob/global"
+	"github.com/benchkram/bob/bob/playbook"
 	"github.com/benchkram/bob/pkg/auth"
+	"github.com/benchkram/bob/pkg/buildhistory"
 	"github.com/benchkram/bob/pkg/dockermobyutil"
+	"github.com/benchkram/bob/pkg/filehash"
+	"github.com/benchkram/bob/pkg/taskduration"
+	"github.com/benchkram/bob/pkg/tasklog"
 	"github.com/benchkram/bob/pkg/usererror"
 
 	"github.com/hashicorp/go-version"
@@ -39,6 +44,19 @@ type B struct {
 	// buildInfoStore stores build infos for tasks.
 	buildInfoStore buildinfostore.Store
 
+	// historyStore records each build.
+	historyStore buildhistory.Store
+
+	// hashCache avoids rehashing unchanged input files.
+	hashCache *filehash.Cache
+
+	// logStore stores the output of task runs.
+	logStore tasklog.Store
+
+	// durationStore keeps the execution times of tasks
+	// to schedule long running tasks first.
+	durationStore *taskduration.Store
+
 	// readConfig some commands need a fully initialised bob.
 	// When this is true a `.bob.workspace` file must exist,
 	// usually done by calling `bob init`
@@ -69,6 +87,24 @@ type B struct {
 	// maxParallel is the maximum number of parallel executed tasks
 	maxParallel int
 
+	// keepGoing continues with independent tasks after a task failed
+	keepGoing bool
+
+	// replayLogs prints the stored output of cached tasks
+	replayLogs bool
+
+	// outputMode determines how the output of tasks is printed
+	outputMode playbook.OutputMode
+
+	// subscribers receive the events of each playbook
+	subscribers []playbook.Subscriber
+
+	// traceReport is the path a chrome trace of the build is written to
+	traceReport string
+
+	// junitReport is the path a junit report of the build is written to
+	junitReport string
+
 	// dockerRegistryClient is used to access the local docker registry
 	dockerRegistryClient dockermobyutil.RegistryClient
 }
@@ -118,6 +154,22 @@ func BobWithBaseStoreDir(baseStoreDir string, opts ...Option) (*B, error) {
 	}
 	bob.buildInfoStore = bis
 
+	hs, err := HistoryStore(baseStoreDir)
+	if err != nil {
+		return nil, err
+	}
+	bob.historyStore = hs
+
+	bob.hashCache = HashCache(baseStoreDir)
+
+	ls, err := LogStore(baseStoreDir)
+	if err != nil {
+		return nil, err
+	}
+	bob.logStore = ls
+
+	bob.durationStore = DurationStore(baseStoreDir)
+
 	authStore, err := AuthStore(baseStoreDir)
 	if err != nil {
 		return nil, err
@@ -165,6 +217,38 @@ func Bob(opts ...Option) (*B, error) {
 		bob.buildInfoStore = bis
 	}
 
+	if bob.historyStore == nil {
+		hs, err := DefaultHistoryStore()
+		if err != nil {
+			return nil, err
+		}
+		bob.historyStore = hs
+	}
+
+	if bob.hashCache == nil {
+		hc, err := DefaultHashCache()
+		if err != nil {
+			return nil, err
+		}
+		bob.hashCache = hc
+	}
+
+	if bob.logStore == nil {
+		ls, err := DefaultLogStore()
+		if err != nil {
+			return nil, err
+		}
+		bob.logStore = ls
+	}
+
+	if bob.durationStore == nil {
+		ds, err := DefaultDurationStore()
+		if err != nil {
+			return nil, err
+		}
+		bob.durationStore = ds
+	}
+
 	if bob.nix == nil {
 		nix, err := DefaultNix()
 		if err != nil {
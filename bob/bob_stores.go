@@ -8,9 +8,14 @@ import (
 
 	"github.com/benchkram/bob/bob/global"
 	"github.com/benchkram/bob/pkg/auth"
+	"github.com/benchkram/bob/pkg/boblog"
+	"github.com/benchkram/bob/pkg/buildhistory"
 	"github.com/benchkram/bob/pkg/buildinfostore"
+	"github.com/benchkram/bob/pkg/filehash"
 	"github.com/benchkram/bob/pkg/store"
 	"github.com/benchkram/bob/pkg/store/filestore"
+	"github.com/benchkram/bob/pkg/taskduration"
+	"github.com/benchkram/bob/pkg/tasklog"
 )
 
 func DefaultFilestore() (s store.Store, err error) {
@@ -61,6 +66,98 @@ func MustDefaultBuildinfoStore() buildinfostore.Store {
 	return s
 }
 
+func DefaultHistoryStore() (s buildhistory.Store, err error) {
+	defer errz.Recover(&err)
+
+	home, err := os.UserHomeDir()
+	errz.Fatal(err)
+
+	return HistoryStore(home)
+}
+
+func HistoryStore(dir string) (s buildhistory.Store, err error) {
+	defer errz.Recover(&err)
+
+	path := filepath.Join(dir, global.BobCacheHistoryFileName)
+	err = os.MkdirAll(filepath.Dir(path), 0775)
+	errz.Fatal(err)
+
+	return buildhistory.New(path), nil
+}
+
+func DefaultLogStore() (s tasklog.Store, err error) {
+	defer errz.Recover(&err)
+
+	home, err := os.UserHomeDir()
+	errz.Fatal(err)
+
+	return LogStore(home)
+}
+
+func LogStore(dir string) (s tasklog.Store, err error) {
+	defer errz.Recover(&err)
+
+	storeDir := filepath.Join(dir, global.BobCacheLogsDir)
+	err = os.MkdirAll(storeDir, 0775)
+	errz.Fatal(err)
+
+	return tasklog.New(storeDir), nil
+}
+
+func DefaultDurationStore() (s *taskduration.Store, err error) {
+	defer errz.Recover(&err)
+
+	home, err := os.UserHomeDir()
+	errz.Fatal(err)
+
+	return DurationStore(home), nil
+}
+
+// DurationStore returns the store of task execution times in the given directory.
+func DurationStore(dir string) *taskduration.Store {
+	return taskduration.New(filepath.Join(dir, global.BobCacheDurationsFileName))
+}
+
+// projectLogStore returns the store of the logs of the project's tasks.
+func (b *B) projectLogStore() tasklog.Store {
+	return b.logStore.Project(b.dir)
+}
+
+// pruneLogs removes old logs of the project's tasks.
+// A failure is only logged as it must not fail the build.
+func (b *B) pruneLogs() {
+	err := b.projectLogStore().Prune()
+	if err != nil {
+		boblog.Log.V(1).Error(err, "Unable to prune task logs")
+	}
+}
+
+func DefaultHashCache() (c *filehash.Cache, err error) {
+	defer errz.Recover(&err)
+
+	home, err := os.UserHomeDir()
+	errz.Fatal(err)
+
+	return HashCache(home), nil
+}
+
+// HashCache returns the cache of input file hashes in the given directory.
+func HashCache(dir string) *filehash.Cache {
+	return filehash.NewCache(filepath.Join(dir, global.BobCacheFileHashesFileName))
+}
+
+// saveHashCache persists the file hashes computed by a build.
+// A failure is only logged as the cache is an optimisation.
+func (b *B) saveHashCache() {
+	if b.hashCache == nil {
+		return
+	}
+	err := b.hashCache.Save()
+	if err != nil {
+		boblog.Log.V(1).Error(err, "Unable to save the hash cache")
+	}
+}
+
 // Localstore returns the local artifact store
 func (b *B) Localstore() store.Store {
 	return b.local
@@ -39,6 +39,8 @@ var (
 	ErrDuplicateTaskName      = fmt.Errorf("duplicate task name")
 	ErrInvalidProjectName     = fmt.Errorf("invalid project name")
 	ErrSelfReference          = fmt.Errorf("self reference")
+	ErrInvalidResource        = fmt.Errorf("invalid resource")
+	ErrInvalidEnv             = fmt.Errorf("invalid environment variable")
 
 	ErrInvalidRunType = fmt.Errorf("Invalid run type")
 
@@ -70,6 +72,14 @@ type Bobfile struct {
 	// Nixpkgs specifies an optional nixpkgs source.
 	Nixpkgs string `yaml:"nixpkgs"`
 
+	// Resources maps a resource pool to the number of tasks
+	// allowed to use it at the same time, e.g. `docker: 2`.
+	Resources map[string]int `yaml:"resources,omitempty"`
+
+	// UseGitignore excludes files ignored by `.gitignore`
+	// files from the inputs of the tasks in this Bobfile.
+	UseGitignore bool `yaml:"useGitignore,omitempty"`
+
 	// Parent directory of the Bobfile.
 	// Populated through BobfileRead().
 	dir string
@@ -293,6 +303,12 @@ func (b *Bobfile) Validate() (err error) {
 		}
 	}
 
+	for name, capacity := range b.Resources {
+		if capacity < 1 {
+			return usererror.Wrap(errors.WithMessagef(ErrInvalidResource, "resource `%s` must allow at least one task", name))
+		}
+	}
+
 	// use for duplicate names validation
 	names := map[string]bool{}
 
@@ -310,6 +326,11 @@ func (b *Bobfile) Validate() (err error) {
 				return errors.WithMessage(ErrSelfReference, name)
 			}
 		}
+
+		err = validateEnv(name, task.EnvDirty, task.PassEnv)
+		if err != nil {
+			return err
+		}
 	}
 
 	for name, run := range b.RTasks {
@@ -326,8 +347,37 @@ func (b *Bobfile) Validate() (err error) {
 				return errors.WithMessage(ErrSelfReference, name)
 			}
 		}
+
+		err = validateEnv(name, run.EnvDirty, run.PassEnv)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateEnv makes sure the names of the variables
+// declared in `env` and `passEnv` of a task are valid.
+// A variable can't be in both, as it's unclear which
+// value is used and whether it's part of the input hash.
+func validateEnv(taskname string, env map[string]string, passEnv []string) error {
+	names := make([]string, 0, len(env)+len(passEnv))
+	for name := range env {
+		names = append(names, name)
 	}
+	names = append(names, passEnv...)
 
+	for _, name := range names {
+		if name == "" || strings.ContainsAny(name, "= ") {
+			return usererror.Wrap(errors.WithMessagef(ErrInvalidEnv, "`%s` of task `%s`", name, taskname))
+		}
+	}
+	for _, name := range passEnv {
+		if _, ok := env[name]; ok {
+			return usererror.Wrap(errors.WithMessagef(ErrInvalidEnv, "`%s` of task `%s` is declared in env and passEnv", name, taskname))
+		}
+	}
 	return nil
 }
 
@@ -75,3 +75,40 @@ func TestBobfileProjectName(t *testing.T) {
 		}
 	}
 }
+
+func TestBobfileValidateInvalidResource(t *testing.T) {
+	b := bobfile.NewBobfile()
+
+	b.Resources = map[string]int{"docker": 0}
+
+	err := b.Validate()
+	if !errors.Is(err, bobfile.ErrInvalidResource) {
+		t.Errorf("Expected %v, got %v", bobfile.ErrInvalidResource, err)
+	}
+}
+
+func TestBobfileValidateInvalidEnv(t *testing.T) {
+	b := bobfile.NewBobfile()
+	b.BTasks["build"] = bobtask.Task{EnvDirty: map[string]string{"A=B": "c"}}
+
+	err := b.Validate()
+	if !errors.Is(err, bobfile.ErrInvalidEnv) {
+		t.Errorf("Expected %v, got %v", bobfile.ErrInvalidEnv, err)
+	}
+
+	b = bobfile.NewBobfile()
+	b.RTasks["server"] = &bobrun.Run{PassEnv: []string{""}}
+
+	err = b.Validate()
+	if !errors.Is(err, bobfile.ErrInvalidEnv) {
+		t.Errorf("Expected %v, got %v", bobfile.ErrInvalidEnv, err)
+	}
+
+	b = bobfile.NewBobfile()
+	b.BTasks["build"] = bobtask.Task{EnvDirty: map[string]string{"A": "b"}, PassEnv: []string{"A"}}
+
+	err = b.Validate()
+	if !errors.Is(err, bobfile.ErrInvalidEnv) {
+		t.Errorf("Expected %v, got %v", bobfile.ErrInvalidEnv, err)
+	}
+}
@@ -3,26 +3,40 @@ package bobfile
 import (
 	"github.com/benchkram/bob/bob/playbook"
 	"github.com/benchkram/bob/bobtask"
+	"github.com/benchkram/bob/pkg/sliceutil"
 )
 
+// Playbook creates a playbook for taskName and it's dependencies.
 func (b *Bobfile) Playbook(taskName string, opts ...playbook.Option) (*playbook.Playbook, error) {
+	return b.PlaybookMultiRoot([]string{taskName}, opts...)
+}
+
+// PlaybookMultiRoot creates a single playbook for multiple tasks and their dependencies.
+// Dependencies shared between tasks are only contained once.
+func (b *Bobfile) PlaybookMultiRoot(taskNames []string, opts ...playbook.Option) (*playbook.Playbook, error) {
+	taskNames = sliceutil.Unique(taskNames)
 
 	pb := playbook.New(
-		taskName,
+		taskNames,
 		opts...,
 	)
 
-	err := b.BTasks.Walk(taskName, "", func(tn string, task bobtask.Task, err error) error {
-		if err != nil {
-			return err
-		}
+	for _, taskName := range taskNames {
+		err := b.BTasks.Walk(taskName, "", func(tn string, task bobtask.Task, err error) error {
+			if err != nil {
+				return err
+			}
 
-		pb.Tasks[tn] = playbook.NewStatus(&task)
+			if _, ok := pb.Tasks[tn]; ok {
+				return nil
+			}
+			pb.Tasks[tn] = playbook.NewStatus(&task)
 
-		return nil
-	})
-	if err != nil {
-		return nil, err
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return pb, nil
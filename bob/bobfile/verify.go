@@ -2,6 +2,9 @@ package bobfile
 
 import (
 	"github.com/benchkram/errz"
+	"github.com/pkg/errors"
+
+	"github.com/benchkram/bob/pkg/usererror"
 )
 
 // Verify a bobfile before task runner.
@@ -31,6 +34,21 @@ func (b *Bobfile) verifyBefore() (err error) {
 		errz.Fatal(err)
 	}
 
+	err = b.verifyResources()
+	errz.Fatal(err)
+
+	return nil
+}
+
+// verifyResources assures tasks only use declared resources.
+func (b *Bobfile) verifyResources() error {
+	for name, task := range b.BTasks {
+		for _, resource := range task.Uses {
+			if _, ok := b.Resources[resource]; !ok {
+				return usererror.Wrap(errors.WithMessagef(ErrInvalidResource, "task `%s` uses undeclared resource `%s`", name, resource))
+			}
+		}
+	}
 	return nil
 }
 
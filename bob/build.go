@@ -3,18 +3,24 @@ package bob
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/benchkram/errz"
 
+	"github.com/benchkram/bob/bob/bobfile"
 	"github.com/benchkram/bob/bob/playbook"
+	"github.com/benchkram/bob/bobtask"
+	"github.com/benchkram/bob/pkg/boblog"
 )
 
 var (
 	ErrNoRebuildRequired = errors.New("no rebuild required")
 )
 
-// Build a task and it's dependencies.
-func (b *B) Build(ctx context.Context, taskName string) (err error) {
+// Build tasks and their dependencies.
+// Multiple tasks are build in a single playbook.
+func (b *B) Build(ctx context.Context, taskNames ...string) (err error) {
 	defer errz.Recover(&err)
 
 	ag, err := b.Aggregate()
@@ -22,14 +28,70 @@ func (b *B) Build(ctx context.Context, taskName string) (err error) {
 
 	b.PrintVersionCompatibility(ag)
 
-	err = b.nix.BuildNixDependenciesInPipeline(ag, taskName)
+	// Building a matrix task builds all its instances.
+	taskNames = bobtask.ExpandMatrixNames(taskNames, ag.BTasks.Matrices())
+
+	err = b.nix.BuildNixDependenciesInPipeline(ag, taskNames...)
 	errz.Fatal(err)
 
 	// Hint: Hash computation (playbook execution) can only start after
 	// nix dependencies are resolved.
 	// Nix dependencies are considered in the input hash of a task.
-	p, err := ag.Playbook(
-		taskName,
+	p, err := ag.PlaybookMultiRoot(
+		taskNames,
+		b.playbookOptions(ag)...,
+	)
+	errz.Fatal(err)
+
+	err = b.play(ctx, taskNames, p)
+	errz.Fatal(err)
+
+	return nil
+}
+
+// play builds the playbook and records the build
+// in the history and the caches of bob.
+func (b *B) play(ctx context.Context, taskNames []string, p *playbook.Playbook) error {
+	start := time.Now()
+	err := p.Build(ctx)
+	b.recordHistory(taskNames, start, p, err)
+	b.saveHashCache()
+	b.saveDurations(p)
+	b.pruneLogs()
+	return err
+}
+
+// DryRun determines if and why tasks and their dependencies
+// would be rebuild. No task is executed.
+func (b *B) DryRun(ctx context.Context, taskNames ...string) (_ []playbook.RebuildDecision, err error) {
+	defer errz.Recover(&err)
+
+	ag, err := b.Aggregate()
+	errz.Fatal(err)
+
+	b.PrintVersionCompatibility(ag)
+
+	// Building a matrix task builds all its instances.
+	taskNames = bobtask.ExpandMatrixNames(taskNames, ag.BTasks.Matrices())
+
+	// Nix dependencies are considered in the input hash of a task.
+	err = b.nix.BuildNixDependenciesInPipeline(ag, taskNames...)
+	errz.Fatal(err)
+
+	p, err := ag.PlaybookMultiRoot(
+		taskNames,
+		b.playbookOptions(ag)...,
+	)
+	errz.Fatal(err)
+	defer b.saveHashCache()
+
+	return p.DryRun(ctx)
+}
+
+// playbookOptions returns the options used to
+// create a playbook from an aggregate.
+func (b *B) playbookOptions(ag *bobfile.Bobfile) []playbook.Option {
+	opts := []playbook.Option{
 		playbook.WithCachingEnabled(b.enableCaching),
 		playbook.WithPredictedNumOfTasks(len(ag.BTasks)),
 		playbook.WithMaxParallel(b.maxParallel),
@@ -37,11 +99,52 @@ func (b *B) Build(ctx context.Context, taskName string) (err error) {
 		playbook.WithLocalStore(b.local),
 		playbook.WithPushEnabled(b.enablePush),
 		playbook.WithPullEnabled(b.enablePull),
-	)
-	errz.Fatal(err)
+		playbook.WithKeepGoing(b.keepGoing),
+		playbook.WithReplayLogs(b.replayLogs),
+		playbook.WithOutputMode(b.outputMode),
+		playbook.WithTraceReport(b.traceReport),
+		playbook.WithJUnitReport(b.junitReport),
+		playbook.WithTaskDurations(b.taskDurations()),
+		playbook.WithResources(ag.Resources),
+	}
+	for _, s := range b.subscribers {
+		opts = append(opts, playbook.WithSubscriber(s))
+	}
+	return opts
+}
 
-	err = p.Build(ctx)
-	errz.Fatal(err)
+// taskDurations returns the execution time of the
+// last run of each task of the project, if known.
+func (b *B) taskDurations() map[string]time.Duration {
+	if b.durationStore == nil {
+		return nil
+	}
 
-	return nil
+	durations, err := b.durationStore.Durations(b.dir)
+	if err != nil {
+		// durations are only used as a scheduling hint.
+		boblog.Log.V(3).Info(fmt.Sprintf("failed to read task durations: %s", err))
+		return nil
+	}
+	return durations
+}
+
+// saveDurations records the execution time of the tasks run by a build.
+// A failure is only logged as durations are only used as a scheduling hint.
+func (b *B) saveDurations(p *playbook.Playbook) {
+	if b.durationStore == nil {
+		return
+	}
+
+	durations := make(map[string]time.Duration)
+	for name, status := range p.Tasks {
+		if status.State() == playbook.StateCompleted && status.RunDuration() > 0 {
+			durations[name] = status.RunDuration()
+		}
+	}
+
+	err := b.durationStore.Update(b.dir, durations)
+	if err != nil {
+		boblog.Log.V(1).Error(err, "Unable to save task durations")
+	}
 }
@@ -6,7 +6,9 @@ import (
 	"github.com/benchkram/errz"
 )
 
-func (b *B) GetBuildTasks() (tasks []string, err error) {
+// GetBuildTasks returns the names of all build tasks,
+// limited to the tasks matching the filters if given.
+func (b *B) GetBuildTasks(filters ...TagFilter) (tasks []string, err error) {
 	defer errz.Recover(&err)
 
 	omitRunTasks := true
@@ -15,6 +17,9 @@ func (b *B) GetBuildTasks() (tasks []string, err error) {
 
 	keys := make([]string, 0, len(aggregate.BTasks))
 	for _, task := range aggregate.BTasks {
+		if !matchAll(filters, task.Tags) {
+			continue
+		}
 		keys = append(keys, task.Name())
 	}
 	sort.Strings(keys)
@@ -14,4 +14,5 @@ var (
 	ErrInvalidScheme               = fmt.Errorf("invalid scheme")
 	ErrInvalidGitUrl               = fmt.Errorf("invalid git url")
 	ErrInvalidRepositoryName       = fmt.Errorf("invalid repository name")
+	ErrCircularDependency          = fmt.Errorf("circular dependency")
 )
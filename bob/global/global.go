@@ -6,6 +6,11 @@ const (
 	BobFileName      = "bob.yaml"
 	BobWorkspaceFile = ".bob.workspace"
 
+	// BobIgnoreFileName holds gitignore-style patterns
+	// excluding files from the inputs of tasks.
+	BobIgnoreFileName = ".bobignore"
+	GitIgnoreFileName = ".gitignore"
+
 	DefaultBuildTask = "build"
 )
 
@@ -18,6 +23,10 @@ var (
 	BobCacheTaskHashesFileName = filepath.Join(BobCacheDir, "hashes")
 	BobCacheArtifactsDir       = filepath.Join(BobCacheDir, "artifacts")
 	BobAuthStoreDir            = filepath.Join(BobCacheDir, "auth")
+	BobCacheHistoryFileName    = filepath.Join(BobCacheDir, "history")
+	BobCacheFileHashesFileName = filepath.Join(BobCacheDir, "filehashes")
+	BobCacheLogsDir            = filepath.Join(BobCacheDir, "logs")
+	BobCacheDurationsFileName  = filepath.Join(BobCacheDir, "durations")
 
 	BobCacheNixFileName = filepath.Join(BobCacheDir, BobNixCacheFile)
 )
@@ -0,0 +1,152 @@
+package bob
+
+import (
+	"context"
+	"sort"
+
+	"github.com/benchkram/errz"
+
+	"github.com/benchkram/bob/bob/bobfile"
+	"github.com/benchkram/bob/bob/playbook"
+	"github.com/benchkram/bob/bobtask"
+	"github.com/benchkram/bob/pkg/boberror"
+	"github.com/benchkram/bob/pkg/nix"
+	"github.com/benchkram/bob/pkg/taskgraph"
+	"github.com/benchkram/bob/pkg/usererror"
+)
+
+// Graph returns the dependency graph of build and run tasks across all
+// bobfiles. The graph is limited to the tasks reachable from taskNames
+// if given. withState determines the rebuild state of each build task
+// the same way `bob build --dry-run` does.
+func (b *B) Graph(ctx context.Context, withState bool, taskNames ...string) (_ *taskgraph.Graph, err error) {
+	defer errz.Recover(&err)
+
+	ag, err := b.Aggregate()
+	errz.Fatal(err)
+
+	taskNames = bobtask.ExpandMatrixNames(taskNames, ag.BTasks.Matrices())
+	names, err := graphTaskNames(ag, taskNames)
+	errz.Fatal(err)
+
+	g := &taskgraph.Graph{Nodes: []taskgraph.Node{}, Edges: []taskgraph.Edge{}}
+	var buildTasks []string
+	for _, name := range names {
+		node := taskgraph.Node{Name: name}
+		var dependsOn []string
+
+		if task, ok := ag.BTasks[name]; ok {
+			node.Kind = taskgraph.KindBuild
+			node.Inputs = task.InputPatterns()
+			filesystem, dockerImages := task.DeclaredTargets()
+			node.Targets = append(filesystem, dockerImages...)
+			node.Dependencies = dependencyNames(task.Dependencies())
+			dependsOn = task.DependsOn
+			buildTasks = append(buildTasks, name)
+		} else {
+			run := ag.RTasks[name]
+			node.Kind = taskgraph.KindRun
+			node.Dependencies = dependencyNames(run.Dependencies())
+			dependsOn = run.DependsOn
+		}
+
+		g.Nodes = append(g.Nodes, node)
+		for _, d := range dependsOn {
+			g.Edges = append(g.Edges, taskgraph.Edge{From: name, To: d})
+		}
+	}
+
+	if withState && len(buildTasks) > 0 {
+		decisions, err := b.DryRun(ctx, buildTasks...)
+		errz.Fatal(err)
+
+		states := make(map[string]playbook.RebuildDecision, len(decisions))
+		for _, d := range decisions {
+			states[d.TaskName] = d
+		}
+		for i, node := range g.Nodes {
+			d, ok := states[node.Name]
+			if !ok {
+				continue
+			}
+			switch {
+			case d.Skipped:
+				g.Nodes[i].State = taskgraph.StateSkipped
+			case d.ArtifactSource != playbook.ArtifactSourceNone:
+				g.Nodes[i].State = taskgraph.StateArtifact
+			case d.RebuildRequired:
+				g.Nodes[i].State = taskgraph.StateRebuild
+			default:
+				g.Nodes[i].State = taskgraph.StateCached
+			}
+			g.Nodes[i].RebuildCause = string(d.Cause)
+		}
+	}
+
+	g.Sort()
+	return g, nil
+}
+
+// graphTaskNames returns the names of the build and run tasks
+// reachable from taskNames, all tasks if no task name is given.
+func graphTaskNames(ag *bobfile.Bobfile, taskNames []string) ([]string, error) {
+	if len(taskNames) == 0 {
+		names := make([]string, 0, len(ag.BTasks)+len(ag.RTasks))
+		for name := range ag.BTasks {
+			names = append(names, name)
+		}
+		for name := range ag.RTasks {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names, nil
+	}
+
+	visited := make(map[string]bool)
+	var visit func(name string) error
+	visit = func(name string) error {
+		if visited[name] {
+			return nil
+		}
+		visited[name] = true
+
+		var dependsOn []string
+		if task, ok := ag.BTasks[name]; ok {
+			dependsOn = task.DependsOn
+		} else if run, ok := ag.RTasks[name]; ok {
+			dependsOn = run.DependsOn
+		} else {
+			return usererror.Wrap(boberror.ErrTaskDoesNotExistF(name))
+		}
+
+		for _, d := range dependsOn {
+			err := visit(d)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, name := range taskNames {
+		err := visit(name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	names := make([]string, 0, len(visited))
+	for name := range visited {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func dependencyNames(deps []nix.Dependency) []string {
+	names := make([]string, 0, len(deps))
+	for _, d := range deps {
+		names = append(names, d.Name)
+	}
+	return names
+}
@@ -0,0 +1,87 @@
+package bob
+
+import (
+	"sort"
+	"time"
+
+	"github.com/benchkram/bob/bob/playbook"
+	"github.com/benchkram/bob/pkg/boblog"
+	"github.com/benchkram/bob/pkg/buildhistory"
+)
+
+// History returns the recorded builds of the project, oldest first.
+func (b *B) History() ([]*buildhistory.Run, error) {
+	runs, err := b.historyStore.Runs()
+	if err != nil {
+		return nil, err
+	}
+
+	// the history is shared by all projects.
+	var project []*buildhistory.Run
+	for _, run := range runs {
+		if run.Project == b.dir {
+			project = append(project, run)
+		}
+	}
+	return project, nil
+}
+
+// HistoryRun returns the recorded build of the project with the given id.
+func (b *B) HistoryRun(id int) (*buildhistory.Run, error) {
+	run, err := b.historyStore.Run(id)
+	if err != nil {
+		return nil, err
+	}
+	if run.Project != b.dir {
+		return nil, buildhistory.ErrRunDoesNotExist
+	}
+	return run, nil
+}
+
+// recordHistory appends a build to the history. A failure to
+// do so is only logged, as it must not fail the build.
+func (b *B) recordHistory(taskNames []string, start time.Time, p *playbook.Playbook, buildErr error) {
+	if b.historyStore == nil {
+		return
+	}
+
+	run := &buildhistory.Run{
+		Project:  b.dir,
+		Tasks:    taskNames,
+		Start:    start,
+		Duration: time.Since(start),
+	}
+	if buildErr != nil {
+		run.Error = buildErr.Error()
+	}
+
+	names := make([]string, 0, len(p.Tasks))
+	for name := range p.Tasks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		status := p.Tasks[name]
+		state := status.State()
+
+		record := buildhistory.Task{
+			Name:           name,
+			State:          state.Short(),
+			RebuildCause:   string(status.RebuildCause()),
+			ArtifactSource: string(status.ArtifactSource()),
+		}
+		// tasks never processed by a worker did not take any time.
+		if status.WorkerID() != 0 {
+			record.Duration = status.ExecutionTime()
+		}
+		record.InputHash = string(status.InputHash())
+
+		run.TaskRecords = append(run.TaskRecords, record)
+	}
+
+	err := b.historyStore.Append(run)
+	if err != nil {
+		boblog.Log.Error(err, "Unable to record build history")
+	}
+}
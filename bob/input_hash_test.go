@@ -0,0 +1,52 @@
+package bob
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecutionNotInInputHash(t *testing.T) {
+	bobfile := `
+build:
+  build:
+    cmd: echo build
+    timeout: 10m
+    retries: 1
+    retryBackoff: 5s
+    uses: [db]
+    exclusive: false
+resources:
+  db: 1
+  gpu: 1
+`
+	b := bobWithBobfiles(t, map[string]string{"bob.yaml": bobfile})
+	hash := func() string {
+		ag, err := b.Aggregate()
+		assert.Nil(t, err)
+		task := ag.BTasks["build"]
+		h, err := task.HashIn()
+		assert.Nil(t, err)
+		return h.String()
+	}
+	initial := hash()
+
+	for _, change := range [][2]string{
+		{"timeout: 10m", "timeout: 1h"},
+		{"retries: 1", "retries: 3"},
+		{"retryBackoff: 5s", "retryBackoff: 1m"},
+		{"uses: [db]", "uses: [db, gpu]"},
+		{"exclusive: false", "exclusive: true"},
+	} {
+		bobfile = strings.Replace(bobfile, change[0], change[1], 1)
+		assert.Nil(t, os.WriteFile("bob.yaml", []byte(bobfile), 0664))
+		assert.Equal(t, initial, hash(), change[1])
+	}
+
+	// the command still changes the hash
+	bobfile = strings.Replace(bobfile, "echo build", "echo changed", 1)
+	assert.Nil(t, os.WriteFile("bob.yaml", []byte(bobfile), 0664))
+	assert.NotEqual(t, initial, hash())
+}
@@ -0,0 +1,6 @@
+package bob
+
+// Logs returns the output of the most recent run of a task.
+func (b *B) Logs(taskname string) ([]byte, error) {
+	return b.projectLogStore().Last(taskname)
+}
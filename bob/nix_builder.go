@@ -7,6 +7,7 @@ import (
 
 	"github.com/benchkram/bob/bob/bobfile"
 	"github.com/benchkram/bob/pkg/nix"
+	"github.com/benchkram/bob/pkg/sliceutil"
 	"github.com/benchkram/bob/pkg/usererror"
 )
 
@@ -39,18 +40,36 @@ func NewNixBuilder(opts ...NixOption) *NixBuilder {
 	return n
 }
 
-// BuildNixDependenciesInPipeline collects and builds nix-dependencies for a pipeline starting at taskName.
-func (n *NixBuilder) BuildNixDependenciesInPipeline(ag *bobfile.Bobfile, taskName string) (err error) {
+// BuildNixDependenciesInPipeline collects and builds nix-dependencies for the pipelines starting at taskNames.
+func (n *NixBuilder) BuildNixDependenciesInPipeline(ag *bobfile.Bobfile, taskNames ...string) (err error) {
 	defer errz.Recover(&err)
 
 	if !nix.IsInstalled() {
 		return usererror.Wrap(fmt.Errorf("nix is not installed on your system. Get it from %s", nix.DownloadURl()))
 	}
 
-	tasksInPipeline, err := ag.BTasks.CollectTasksInPipeline(taskName)
-	errz.Fatal(err)
+	var tasksInPipeline []string
+	for _, taskName := range taskNames {
+		tasks, err := ag.BTasks.CollectTasksInPipeline(taskName)
+		errz.Fatal(err)
+		tasksInPipeline = append(tasksInPipeline, tasks...)
+	}
+
+	// Tasks with a false condition are skipped,
+	// their nix dependencies don't need to be built.
+	var tasksToBuild []string
+	for _, name := range sliceutil.Unique(tasksInPipeline) {
+		t := ag.BTasks[name]
+		met, err := t.ConditionMet()
+		errz.Fatal(err)
+		// keep the evaluated condition
+		ag.BTasks[name] = t
+		if met {
+			tasksToBuild = append(tasksToBuild, name)
+		}
+	}
 
-	return n.BuildNixDependencies(ag, tasksInPipeline, []string{})
+	return n.BuildNixDependencies(ag, tasksToBuild, []string{})
 }
 
 // BuildNixDependencies builds nix dependencies and prepares the affected tasks
@@ -1,9 +1,14 @@
 package bob
 
 import (
+	"github.com/benchkram/bob/bob/playbook"
 	"github.com/benchkram/bob/pkg/auth"
+	"github.com/benchkram/bob/pkg/buildhistory"
 	"github.com/benchkram/bob/pkg/buildinfostore"
+	"github.com/benchkram/bob/pkg/filehash"
 	"github.com/benchkram/bob/pkg/store"
+	"github.com/benchkram/bob/pkg/taskduration"
+	"github.com/benchkram/bob/pkg/tasklog"
 )
 
 type Option func(b *B)
@@ -46,6 +51,30 @@ func WithBuildinfoStore(store buildinfostore.Store) Option {
 	}
 }
 
+func WithHistoryStore(store buildhistory.Store) Option {
+	return func(b *B) {
+		b.historyStore = store
+	}
+}
+
+func WithHashCache(cache *filehash.Cache) Option {
+	return func(b *B) {
+		b.hashCache = cache
+	}
+}
+
+func WithLogStore(store tasklog.Store) Option {
+	return func(b *B) {
+		b.logStore = store
+	}
+}
+
+func WithDurationStore(store *taskduration.Store) Option {
+	return func(b *B) {
+		b.durationStore = store
+	}
+}
+
 func WithCachingEnabled(enabled bool) Option {
 	return func(b *B) {
 		b.enableCaching = enabled
@@ -87,3 +116,44 @@ func WithMaxParallel(maxParallel int) Option {
 		b.maxParallel = maxParallel
 	}
 }
+
+func WithKeepGoing(keepGoing bool) Option {
+	return func(b *B) {
+		b.keepGoing = keepGoing
+	}
+}
+
+// WithOutputMode determines how the output of tasks is printed.
+func WithOutputMode(mode playbook.OutputMode) Option {
+	return func(b *B) {
+		b.outputMode = mode
+	}
+}
+
+// WithReplayLogs prints the stored output
+// of tasks which don't need to be rebuild.
+func WithReplayLogs(replay bool) Option {
+	return func(b *B) {
+		b.replayLogs = replay
+	}
+}
+
+// WithSubscriber adds a subscriber receiving
+// the task events of each build.
+func WithSubscriber(s playbook.Subscriber) Option {
+	return func(b *B) {
+		b.subscribers = append(b.subscribers, s)
+	}
+}
+
+func WithTraceReport(path string) Option {
+	return func(b *B) {
+		b.traceReport = path
+	}
+}
+
+func WithJUnitReport(path string) Option {
+	return func(b *B) {
+		b.junitReport = path
+	}
+}
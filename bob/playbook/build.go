@@ -5,11 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/benchkram/bob/bobtask"
 	"github.com/benchkram/bob/bobtask/hash"
 	"github.com/benchkram/bob/pkg/boblog"
+	"github.com/benchkram/bob/pkg/usererror"
 )
 
 // Build the playbook starting at root.
@@ -34,6 +37,10 @@ func (p *Playbook) Build(ctx context.Context) (err error) {
 			for t := range queue {
 				processing.Add(1)
 				boblog.Log.V(5).Info(fmt.Sprintf("RUNNING task %s on worker  %d ", t.Name(), workerID))
+				if status, ok := p.Tasks[t.Name()]; ok {
+					status.SetWorkerID(workerID)
+					status.SetWorkerStart(time.Now())
+				}
 				err := p.build(ctx, t)
 				if err != nil {
 					processingErrorsMutex.Lock()
@@ -43,8 +50,12 @@ func (p *Playbook) Build(ctx context.Context) (err error) {
 					// Any error occurred during a build puts the
 					// playbook in a done state. This prevents
 					// further tasks be queued for execution.
-
-					p.Done()
+					//
+					// In keep-going mode only the dependents of
+					// the failed task are canceled.
+					if !p.keepGoing {
+						p.Done()
+					}
 				}
 
 				processedTasks = append(processedTasks, t)
@@ -86,6 +97,8 @@ func (p *Playbook) Build(ctx context.Context) (err error) {
 
 	close(queue)
 
+	p.printOutput()
+
 	// iterate through tasks and logs
 	// skipped input files.
 	var skippedInputs int
@@ -102,11 +115,26 @@ func (p *Playbook) Build(ctx context.Context) (err error) {
 	// sync any newly generated artifacts with the remote store
 	if p.enablePush {
 		for taskName, artifact := range p.inputHashes(true) {
-			p.pushArtifact(ctx, artifact, taskName)
+			sync := p.pushArtifact(ctx, artifact, taskName)
+			if sync != nil {
+				task := p.Tasks[taskName]
+				task.SetArtifactSync(sync)
+				p.emit(EventArtifactPushed, task)
+			}
 		}
 	}
 
+	err = p.writeReports()
+	if err != nil {
+		processingErrors = append(processingErrors, err)
+	}
+
 	if len(processingErrors) > 0 {
+		if p.keepGoing && len(processingErrors) > 1 {
+			failed := &FailedTasksError{Errs: processingErrors}
+			return usererror.Wrap(failed).WithSummary(failed.Summary())
+		}
+
 		// Pass only the very first processing error.
 		return processingErrors[0]
 	}
@@ -114,6 +142,38 @@ func (p *Playbook) Build(ctx context.Context) (err error) {
 	return nil
 }
 
+// FailedTasksError is returned by a playbook in keep-going mode
+// when multiple tasks failed.
+type FailedTasksError struct {
+	Errs []error
+}
+
+func (e *FailedTasksError) Error() string {
+	msgs := make([]string, 0, len(e.Errs))
+	for _, err := range e.Errs {
+		msgs = append(msgs, err.Error())
+	}
+	return fmt.Sprintf("%d tasks failed:\n%s", len(e.Errs), strings.Join(msgs, "\n"))
+}
+
+// Summary joins the summaries of the failed tasks.
+func (e *FailedTasksError) Summary() string {
+	summaries := make([]string, 0, len(e.Errs))
+	for _, err := range e.Errs {
+		var uerr *usererror.E
+		if errors.As(err, &uerr) && uerr.Summary() != "" {
+			summaries = append(summaries, uerr.Summary())
+		}
+	}
+	return strings.Join(summaries, "\n\n")
+}
+
+// Unwrap returns the first error to allow
+// matching with errors.Is & errors.As.
+func (e *FailedTasksError) Unwrap() error {
+	return e.Errs[0]
+}
+
 const maxSkippedInputs = 5
 
 // logSkippedInputs until max is reached
@@ -1,15 +1,18 @@
 package playbook
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"io"
 	"sync"
+	"time"
 
 	"github.com/benchkram/bob/bobtask"
 	"github.com/benchkram/bob/pkg/boblog"
 	"github.com/benchkram/errz"
+	"github.com/logrusorgru/aurora"
 )
 
 // didWriteBuildOutput assures that a new line is added
@@ -21,37 +24,74 @@ var didWriteBuildOutput bool
 func (p *Playbook) build(ctx context.Context, task *bobtask.Task) (err error) {
 	defer errz.Recover(&err)
 
+	coloredName := task.ColoredName()
+
+	taskStatus, err := p.TaskStatus(task.Name())
+	errz.Fatal(err)
+
 	// A task is flagged successful before
 	var taskSuccessFul bool
 	var taskErr error
+	// targetsTouched is set once the targets of
+	// the task are about to be extracted or build.
+	var targetsTouched bool
+	// output collects the output of the task
+	// unless it's streamed.
+	output := p.newOutputBuffer()
 	defer func() {
-		if !taskSuccessFul {
-			errr := p.TaskFailed(task.Name(), taskErr)
+		if taskSuccessFul {
+			return
+		}
+
+		// A canceled build leaves tasks in the canceled state
+		// and removes what they might have partially written.
+		// Their output is dropped to not bury the output of
+		// the failure which likely caused the cancellation.
+		if errors.Is(ctx.Err(), context.Canceled) {
+			if targetsTouched {
+				p.rollback(taskStatus)
+			}
+			boblog.Log.V(1).Info(fmt.Sprintf("%-*s\t%s", p.namePad, coloredName, StateCanceled))
+			errr := p.buildCanceled(task.Name())
 			if errr != nil {
-				boblog.Log.Error(errr, "Setting the task state to failed, failed.")
+				boblog.Log.Error(errr, "Setting the task state to canceled, failed.")
 			}
+			return
 		}
-	}()
-
-	coloredName := task.ColoredName()
 
-	done := make(chan struct{})
-	defer close(done)
+		p.collectOutput(task, output, true)
 
-	go func() {
-		select {
-		case <-done:
-		case <-ctx.Done():
-			if errors.Is(ctx.Err(), context.Canceled) {
-				boblog.Log.V(1).Info(fmt.Sprintf("%-*s\t%s", p.namePad, coloredName, StateCanceled))
-				_ = p.TaskCanceled(task.Name())
-			}
+		errr := p.TaskFailed(task.Name(), taskErr)
+		if errr != nil {
+			boblog.Log.Error(errr, "Setting the task state to failed, failed.")
 		}
 	}()
 
+	// The build might have been canceled while
+	// the task was waiting for a worker.
+	errz.Fatal(ctx.Err())
+
+	// Tasks with a false condition are skipped,
+	// their dependents still proceed.
+	conditionMet, err := task.ConditionMet()
+	if err != nil {
+		taskErr = err
+	}
+	errz.Fatal(err)
+	if !conditionMet {
+		status := StateSkipped
+		boblog.Log.V(1).Info(fmt.Sprintf("%-*s\t%s", p.namePad, coloredName, aurora.Faint(status.Short())))
+		taskSuccessFul = true
+		return p.TaskSkipped(task.Name())
+	}
+
 	rebuildRequired, rebuildCause, err := p.TaskNeedsRebuild(task.Name())
 	errz.Fatal(err)
 	boblog.Log.V(2).Info(fmt.Sprintf("TaskNeedsRebuild [rebuildRequired: %t] [cause:%s]", rebuildRequired, rebuildCause))
+	taskStatus.SetRebuildCause(rebuildCause)
+	if h, ok := task.HashInCached(); ok {
+		taskStatus.SetInputHash(h)
+	}
 
 	// task might need a rebuild due to an input change.
 	// Could still be possible to load the targets from the artifact store.
@@ -63,13 +103,14 @@ func (p *Playbook) build(ctx context.Context, task *bobtask.Task) (err error) {
 			errz.Fatal(err)
 
 			// download artifact if it exists on the remote. if exists locally will use that one
-			p.downloadArtifact(ctx, hashIn, task.ColoredName(), false)
+			taskStatus.SetArtifactSync(p.downloadArtifact(ctx, hashIn, task.ColoredName(), false))
 
+			targetsTouched = true
 			success, err := task.ArtifactExtract(hashIn)
 			if err != nil {
 				// if local artifact is corrupted due to incomplete previous download, try a fresh download
 				if errors.Is(err, io.ErrUnexpectedEOF) {
-					p.downloadArtifact(ctx, hashIn, task.ColoredName(), true)
+					taskStatus.SetArtifactSync(p.downloadArtifact(ctx, hashIn, task.ColoredName(), true))
 					success, err = task.ArtifactExtract(hashIn)
 				}
 			}
@@ -77,6 +118,7 @@ func (p *Playbook) build(ctx context.Context, task *bobtask.Task) (err error) {
 			errz.Fatal(err)
 			if success {
 				rebuildRequired = false
+				taskStatus.SetArtifactSource(artifactSource(taskStatus.ArtifactSync()))
 
 				// In case an artifact was synced from the remote store no buildinfo exists...
 				// To avoid subsequent artifact extraction the Buildinfo is created after
@@ -90,10 +132,12 @@ func (p *Playbook) build(ctx context.Context, task *bobtask.Task) (err error) {
 			boblog.Log.V(2).Info(fmt.Sprintf("%-*s\t%s, extracting artifact", p.namePad, coloredName, rebuildCause))
 			hashIn, err := task.HashIn()
 			errz.Fatal(err)
+			targetsTouched = true
 			success, err := task.ArtifactExtract(hashIn)
 			errz.Fatal(err)
 			if success {
 				rebuildRequired = false
+				taskStatus.SetArtifactSource(ArtifactSourceLocal)
 			}
 		case TargetNotInLocalStore:
 		case TaskForcedRebuild:
@@ -105,6 +149,9 @@ func (p *Playbook) build(ctx context.Context, task *bobtask.Task) (err error) {
 	if !rebuildRequired {
 		status := StateNoRebuildRequired
 		boblog.Log.V(2).Info(fmt.Sprintf("%-*s\t%s", p.namePad, coloredName, status.Short()))
+		if p.replayLogs {
+			p.replayLog(task)
+		}
 		taskSuccessFul = true
 		return p.TaskNoRebuildRequired(task.Name())
 	}
@@ -116,10 +163,8 @@ func (p *Playbook) build(ctx context.Context, task *bobtask.Task) (err error) {
 	}
 	didWriteBuildOutputMu.Unlock()
 
-	err = task.Clean()
-	errz.Fatal(err)
-
-	err = task.Run(ctx, p.namePad)
+	targetsTouched = true
+	err = p.run(ctx, taskStatus, output)
 	if err != nil {
 		taskSuccessFul = false
 		taskErr = err
@@ -134,6 +179,8 @@ func (p *Playbook) build(ctx context.Context, task *bobtask.Task) (err error) {
 	// flagged as failed in a defered function call.
 	taskSuccessFul = true
 
+	p.collectOutput(task, output, false)
+
 	err = p.TaskCompleted(task.Name())
 	if err != nil {
 		if errors.Is(err, ErrFailed) {
@@ -142,11 +189,91 @@ func (p *Playbook) build(ctx context.Context, task *bobtask.Task) (err error) {
 	}
 	errz.Fatal(err)
 
-	taskStatus, err := p.TaskStatus(task.Name())
-	errz.Fatal(err)
-
 	state := taskStatus.State()
 	boblog.Log.V(1).Info(fmt.Sprintf("%-*s\t%s", p.namePad, coloredName, "..."+state.Short()))
 
 	return nil
 }
+
+// artifactSource determines if an extracted artifact
+// was pulled from the remote store beforehand.
+func artifactSource(sync *ArtifactSync) ArtifactSource {
+	if sync != nil && sync.Direction == SyncPull && sync.Result == SyncResultSynced {
+		return ArtifactSourceRemote
+	}
+	return ArtifactSourceLocal
+}
+
+// replayLog prints the stored output of a task.
+// A missing log is not considered an error.
+func (p *Playbook) replayLog(task *bobtask.Task) {
+	didWriteBuildOutputMu.Lock()
+	defer didWriteBuildOutputMu.Unlock()
+
+	if !didWriteBuildOutput {
+		boblog.Log.V(1).Info("")
+		didWriteBuildOutput = true
+	}
+
+	replayed, err := task.ReplayLog(p.namePad)
+	if err != nil {
+		boblog.Log.Error(err, fmt.Sprintf("Unable to replay the log of task %s", task.Name()))
+		return
+	}
+	if !replayed {
+		boblog.Log.V(1).Info(fmt.Sprintf("%-*s\t%s", p.namePad, task.ColoredName(), aurora.Faint("no log stored")))
+	}
+}
+
+// rollback removes the partially written targets of a canceled task
+// and an incompletely downloaded artifact from the local store.
+func (p *Playbook) rollback(taskStatus *Status) {
+	boblog.Log.V(2).Info(fmt.Sprintf("%-*s\t%s", p.namePad, taskStatus.ColoredName(), "rolling back"))
+
+	err := taskStatus.Clean()
+	if err != nil {
+		boblog.Log.Error(err, fmt.Sprintf("Unable to clean targets of canceled task %s", taskStatus.Name()))
+	}
+
+	sync := taskStatus.ArtifactSync()
+	if sync != nil && sync.Direction == SyncPull && sync.Result == SyncResultFailed && p.localStore != nil {
+		err = p.localStore.ArtifactRemove(context.Background(), sync.ArtifactID)
+		if err != nil {
+			boblog.Log.Error(err, fmt.Sprintf("Unable to remove incomplete artifact %s", sync.ArtifactID))
+		}
+	}
+}
+
+// run executes the commands of a task. A failed task
+// is retried according to the retry policy of the task.
+func (p *Playbook) run(ctx context.Context, taskStatus *Status, output *bytes.Buffer) error {
+	task := taskStatus.Task
+	backoff := task.RetryBackoff()
+
+	for attempt := 0; ; attempt++ {
+		err := task.Clean()
+		if err != nil {
+			return err
+		}
+
+		p.emit(EventRunning, taskStatus)
+		start := time.Now()
+		err = task.Run(ctx, p.namePad, outputWriter(output))
+		taskStatus.SetRunDuration(time.Since(start))
+		if err == nil || attempt >= task.Retries || ctx.Err() != nil {
+			return err
+		}
+
+		boblog.Log.V(1).Info(fmt.Sprintf("%-*s\t%s", p.namePad, task.ColoredName(),
+			aurora.Yellow(fmt.Sprintf("failed, retrying (%d/%d)", attempt+1, task.Retries))))
+
+		if backoff > 0 {
+			select {
+			case <-ctx.Done():
+				return err
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+	}
+}
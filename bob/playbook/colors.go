@@ -3,6 +3,7 @@ package playbook
 import (
 	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/benchkram/bob/pkg/boblog"
 	"github.com/logrusorgru/aurora"
@@ -49,7 +50,14 @@ func (p *Playbook) pickTaskColors() {
 	}
 	p.namePad += 14
 
-	dependencies := len(tasks) - 1
-	rootName := p.Tasks[p.root].ColoredName()
-	boblog.Log.V(1).Info(fmt.Sprintf("Running task %s with %d dependencies", rootName, dependencies))
+	dependencies := len(tasks) - len(p.roots)
+	rootNames := make([]string, 0, len(p.roots))
+	for _, root := range p.roots {
+		rootNames = append(rootNames, p.Tasks[root].ColoredName())
+	}
+	if len(rootNames) == 1 {
+		boblog.Log.V(1).Info(fmt.Sprintf("Running task %s with %d dependencies", rootNames[0], dependencies))
+	} else {
+		boblog.Log.V(1).Info(fmt.Sprintf("Running tasks %s with %d dependencies", strings.Join(rootNames, ", "), dependencies))
+	}
 }
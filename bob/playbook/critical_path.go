@@ -0,0 +1,75 @@
+package playbook
+
+import (
+	"time"
+)
+
+// criticalPath returns the historical duration of a task plus the
+// duration of the longest chain of tasks depending on it. Tasks without
+// a known duration are considered to take no time.
+//
+// Must be called with the playMutex held.
+func (p *Playbook) criticalPath(taskname string) time.Duration {
+	if p.criticalPaths == nil {
+		p.criticalPaths = p.computeCriticalPaths()
+	}
+	return p.criticalPaths[taskname]
+}
+
+func (p *Playbook) computeCriticalPaths() map[string]time.Duration {
+	// dependents maps a task to the tasks depending on it.
+	dependents := make(map[string][]string, len(p.Tasks))
+	for name, task := range p.Tasks {
+		for _, dependency := range task.DependsOn {
+			dependents[dependency] = append(dependents[dependency], name)
+		}
+	}
+
+	paths := make(map[string]time.Duration, len(p.Tasks))
+	visiting := make(map[string]bool)
+
+	var compute func(taskname string) time.Duration
+	compute = func(taskname string) time.Duration {
+		if d, ok := paths[taskname]; ok {
+			return d
+		}
+		if visiting[taskname] {
+			// cycles are rejected elsewhere,
+			// avoid endless recursion anyway.
+			return 0
+		}
+		visiting[taskname] = true
+
+		var longest time.Duration
+		for _, dependent := range dependents[taskname] {
+			if d := compute(dependent); d > longest {
+				longest = d
+			}
+		}
+
+		visiting[taskname] = false
+		paths[taskname] = p.durations[taskname] + longest
+		return paths[taskname]
+	}
+
+	for name := range p.Tasks {
+		compute(name)
+	}
+
+	return paths
+}
+
+// nextTask picks the task with the longest critical path from the
+// ready tasks. Ties are resolved by the order of the candidates.
+//
+// Must be called with the playMutex held.
+func (p *Playbook) nextTask(candidates []string) string {
+	next := candidates[0]
+	longest := p.criticalPath(next)
+	for _, candidate := range candidates[1:] {
+		if d := p.criticalPath(candidate); d > longest {
+			next, longest = candidate, d
+		}
+	}
+	return next
+}
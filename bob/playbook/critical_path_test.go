@@ -0,0 +1,58 @@
+package playbook
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/benchkram/bob/bobtask"
+)
+
+// newCriticalPathPlaybook creates a playbook for
+//
+//	all -> short
+//	all -> chain -> leaf
+func newCriticalPathPlaybook(opts ...Option) *Playbook {
+	p := New([]string{"all"}, opts...)
+
+	for name, dependsOn := range map[string][]string{
+		"all":   {"short", "chain"},
+		"short": {},
+		"chain": {"leaf"},
+		"leaf":  {},
+	} {
+		task := bobtask.Make()
+		task.SetName(name)
+		task.DependsOn = dependsOn
+		p.Tasks[name] = NewStatus(&task)
+	}
+
+	return p
+}
+
+func TestCriticalPath(t *testing.T) {
+	p := newCriticalPathPlaybook(WithTaskDurations(map[string]time.Duration{
+		"short": 5 * time.Second,
+		"chain": 10 * time.Second,
+		"leaf":  time.Second,
+	}))
+
+	assert.Equal(t, 11*time.Second, p.criticalPath("leaf"))
+	assert.Equal(t, 10*time.Second, p.criticalPath("chain"))
+	assert.Equal(t, 5*time.Second, p.criticalPath("short"))
+	assert.Equal(t, time.Duration(0), p.criticalPath("all"))
+
+	// leaf is on the longer path and must be started first.
+	assert.Nil(t, p.Play())
+	next := <-p.TaskChannel()
+	assert.Equal(t, "leaf", next.Name())
+}
+
+func TestCriticalPathWithoutDurations(t *testing.T) {
+	p := newCriticalPathPlaybook()
+
+	// without history the order of discovery is kept.
+	assert.Equal(t, "short", p.nextTask([]string{"short", "leaf"}))
+	assert.Equal(t, "leaf", p.nextTask([]string{"leaf", "short"}))
+}
@@ -0,0 +1,119 @@
+package playbook
+
+import (
+	"context"
+
+	"github.com/benchkram/errz"
+)
+
+// ArtifactSource describes from where the targets
+// of a task are loaded instead of running the task.
+type ArtifactSource string
+
+const (
+	ArtifactSourceNone   ArtifactSource = ""
+	ArtifactSourceLocal  ArtifactSource = "local"
+	ArtifactSourceRemote ArtifactSource = "remote"
+)
+
+// RebuildDecision describes what a build
+// would do with a task and why.
+type RebuildDecision struct {
+	TaskName string
+
+	// RebuildRequired is true when the task's commands would be executed.
+	RebuildRequired bool
+
+	// Cause is the reason the cache missed,
+	// empty if the task is cached.
+	Cause RebuildCause
+
+	// ArtifactSource is set when the targets of the task are
+	// loaded from an artifact store instead of rebuilding the task.
+	ArtifactSource ArtifactSource
+
+	// Skipped is true when the task's condition is false.
+	Skipped bool
+}
+
+// DryRun determines the rebuild decision for each task of the playbook
+// without executing any task. Decisions are returned in the order tasks
+// would complete, children before their parents.
+//
+// DryRun alters the state of the tasks,
+// the playbook can't be used for a build afterwards.
+func (p *Playbook) DryRun(ctx context.Context) (decisions []RebuildDecision, err error) {
+	defer errz.Recover(&err)
+
+	for _, taskname := range p.Tasks.postOrder(p.roots) {
+		decision, err := p.rebuildDecision(ctx, taskname)
+		errz.Fatal(err)
+
+		// Pretend the task was processed so
+		// that parent tasks can detect changed children.
+		state := StateNoRebuildRequired
+		switch {
+		case decision.Skipped:
+			state = StateSkipped
+		case decision.RebuildRequired:
+			state = StateCompleted
+		}
+		err = p.setTaskState(taskname, state, nil)
+		errz.Fatal(err)
+
+		decisions = append(decisions, decision)
+	}
+
+	return decisions, nil
+}
+
+// rebuildDecision mirrors the decisions taken in build()
+// without extracting or downloading any artifacts.
+func (p *Playbook) rebuildDecision(ctx context.Context, taskname string) (_ RebuildDecision, err error) {
+	defer errz.Recover(&err)
+
+	decision := RebuildDecision{TaskName: taskname}
+
+	conditionMet, err := p.Tasks[taskname].Task.ConditionMet()
+	errz.Fatal(err)
+	if !conditionMet {
+		decision.Skipped = true
+		return decision, nil
+	}
+
+	rebuildRequired, rebuildCause, err := p.TaskNeedsRebuild(taskname)
+	errz.Fatal(err)
+	decision.RebuildRequired = rebuildRequired
+	decision.Cause = rebuildCause
+
+	if !rebuildRequired {
+		return decision, nil
+	}
+
+	task := p.Tasks[taskname].Task
+	switch rebuildCause {
+	case InputNotFoundInBuildInfo:
+		hashIn, err := task.HashIn()
+		errz.Fatal(err)
+
+		if task.ArtifactExists(hashIn) {
+			decision.RebuildRequired = false
+			decision.ArtifactSource = ArtifactSourceLocal
+		} else if p.enablePull && p.enableCaching && p.remoteStore != nil && p.localStore != nil {
+			if p.remoteStore.ArtifactExists(ctx, hashIn.String()) {
+				decision.RebuildRequired = false
+				decision.ArtifactSource = ArtifactSourceRemote
+			}
+		}
+	case TargetInvalid:
+		hashIn, err := task.HashIn()
+		errz.Fatal(err)
+
+		if task.ArtifactExists(hashIn) {
+			decision.RebuildRequired = false
+			decision.ArtifactSource = ArtifactSourceLocal
+		}
+	}
+
+	return decision, nil
+}
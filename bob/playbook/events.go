@@ -0,0 +1,135 @@
+package playbook
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+type EventType string
+
+const (
+	EventQueued            EventType = "queued"
+	EventRunning           EventType = "running"
+	EventCompleted         EventType = "completed"
+	EventNoRebuildRequired EventType = "no-rebuild-required"
+	EventSkipped           EventType = "skipped"
+	EventFailed            EventType = "failed"
+	EventCanceled          EventType = "canceled"
+
+	// EventArtifactPushed is emitted for each artifact
+	// pushed to the remote store after the build.
+	EventArtifactPushed EventType = "artifact-pushed"
+)
+
+// Event describes a state transition of a task in the playbook.
+type Event struct {
+	Type EventType `json:"type"`
+	Task string    `json:"task"`
+	Time time.Time `json:"time"`
+
+	InputHash    string       `json:"input_hash,omitempty"`
+	RebuildCause RebuildCause `json:"rebuild_cause,omitempty"`
+
+	// Start and End of the task processing,
+	// End is only set once a task is done.
+	Start *time.Time `json:"start,omitempty"`
+	End   *time.Time `json:"end,omitempty"`
+
+	Error string `json:"error,omitempty"`
+
+	// Artifact contains the result of pulling or pushing the
+	// task's artifact from/to the remote store, if any.
+	Artifact *ArtifactSync `json:"artifact,omitempty"`
+}
+
+// Subscriber is called for each event of a playbook.
+// Calls are serialized, subscribers must not block.
+type Subscriber func(Event)
+
+// JSONSubscriber writes each event as a single line of json to w.
+func JSONSubscriber(w io.Writer) Subscriber {
+	enc := json.NewEncoder(w)
+	return func(e Event) {
+		_ = enc.Encode(e)
+	}
+}
+
+// subscribers holds the subscribers of a playbook
+type subscribers struct {
+	mu   sync.Mutex
+	subs []Subscriber
+}
+
+// Subscribe adds a subscriber receiving the events of the playbook.
+// Must be called before the playbook is started.
+func (p *Playbook) Subscribe(s Subscriber) {
+	p.subscribers.mu.Lock()
+	defer p.subscribers.mu.Unlock()
+	p.subscribers.subs = append(p.subscribers.subs, s)
+}
+
+// eventType maps task states to events.
+func eventType(s State) EventType {
+	switch s {
+	case StateRunning:
+		// Tasks are set to running when they are passed to
+		// the worker pool, execution might not have started yet.
+		return EventQueued
+	case StateCompleted:
+		return EventCompleted
+	case StateNoRebuildRequired:
+		return EventNoRebuildRequired
+	case StateSkipped:
+		return EventSkipped
+	case StateFailed:
+		return EventFailed
+	case StateCanceled:
+		return EventCanceled
+	default:
+		return ""
+	}
+}
+
+// emit an event of the given type for a task to all subscribers.
+func (p *Playbook) emit(t EventType, task *Status) {
+	p.subscribers.mu.Lock()
+	defer p.subscribers.mu.Unlock()
+
+	if len(p.subscribers.subs) == 0 || t == "" {
+		return
+	}
+
+	e := Event{
+		Type:         t,
+		Task:         task.Name(),
+		Time:         time.Now(),
+		RebuildCause: task.RebuildCause(),
+		Artifact:     task.ArtifactSync(),
+	}
+
+	// The input hash is only known once
+	// it was computed while building the task.
+	e.InputHash = string(task.InputHash())
+
+	switch task.State() {
+	case StatePending:
+	default:
+		start := task.Start()
+		e.Start = &start
+	}
+	switch task.State() {
+	case StateCompleted, StateCanceled, StateNoRebuildRequired, StateSkipped, StateFailed:
+		end := task.End()
+		e.End = &end
+	}
+
+	if task.Error != nil {
+		e.Error = task.Error.Error()
+	}
+
+	for _, s := range p.subscribers.subs {
+		s(e)
+	}
+}
@@ -1,6 +1,10 @@
 package playbook
 
-import "github.com/benchkram/bob/pkg/store"
+import (
+	"time"
+
+	"github.com/benchkram/bob/pkg/store"
+)
 
 type Option func(p *Playbook)
 
@@ -45,3 +49,57 @@ func WithLocalStore(s store.Store) Option {
 		p.localStore = s
 	}
 }
+
+func WithKeepGoing(keepGoing bool) Option {
+	return func(p *Playbook) {
+		p.keepGoing = keepGoing
+	}
+}
+
+func WithSubscriber(s Subscriber) Option {
+	return func(p *Playbook) {
+		p.Subscribe(s)
+	}
+}
+
+func WithTraceReport(path string) Option {
+	return func(p *Playbook) {
+		p.traceReport = path
+	}
+}
+
+func WithJUnitReport(path string) Option {
+	return func(p *Playbook) {
+		p.junitReport = path
+	}
+}
+
+func WithOutputMode(mode OutputMode) Option {
+	return func(p *Playbook) {
+		p.outputMode = mode
+	}
+}
+
+// WithReplayLogs prints the stored output
+// of tasks which don't need to be rebuild.
+func WithReplayLogs(replay bool) Option {
+	return func(p *Playbook) {
+		p.replayLogs = replay
+	}
+}
+
+// WithTaskDurations sets the historical execution times
+// of tasks used to prioritize tasks on the critical path.
+func WithTaskDurations(durations map[string]time.Duration) Option {
+	return func(p *Playbook) {
+		p.durations = durations
+	}
+}
+
+// WithResources sets the capacity of resource pools
+// limiting the number of tasks using a pool in parallel.
+func WithResources(resources map[string]int) Option {
+	return func(p *Playbook) {
+		p.resources = resources
+	}
+}
@@ -0,0 +1,109 @@
+package playbook
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/benchkram/bob/bobtask"
+	"github.com/benchkram/bob/pkg/boblog"
+)
+
+// OutputMode determines how the output of tasks is printed.
+type OutputMode string
+
+const (
+	// OutputStream prints the output of tasks line by line as it's written.
+	OutputStream OutputMode = "stream"
+	// OutputGrouped prints the output of each task in one block
+	// after the build, the blocks of failed tasks first.
+	OutputGrouped OutputMode = "grouped"
+	// OutputFailedOnly prints the output of failed tasks in one block
+	// after the build.
+	OutputFailedOnly OutputMode = "failed-only"
+)
+
+var ErrInvalidOutputMode = fmt.Errorf("invalid output mode")
+
+// ParseOutputMode validates the name of an output mode.
+func ParseOutputMode(s string) (OutputMode, error) {
+	switch mode := OutputMode(s); mode {
+	case OutputStream, OutputGrouped, OutputFailedOnly:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("%w %q, supported: %s, %s, %s", ErrInvalidOutputMode, s, OutputStream, OutputGrouped, OutputFailedOnly)
+	}
+}
+
+// newOutputBuffer returns the buffer the output of a task is
+// collected in, nil in case the output is streamed.
+func (p *Playbook) newOutputBuffer() *bytes.Buffer {
+	if p.outputMode == "" || p.outputMode == OutputStream {
+		return nil
+	}
+	return &bytes.Buffer{}
+}
+
+// outputWriter avoids passing a nil buffer as non-nil writer.
+func outputWriter(output *bytes.Buffer) io.Writer {
+	if output == nil {
+		return nil
+	}
+	return output
+}
+
+// outputBlock is the collected output of a task.
+type outputBlock struct {
+	task   *bobtask.Task
+	output []byte
+	failed bool
+}
+
+// collectOutput keeps the output of a task to be printed
+// in one block by printOutput once the build is done.
+// The output of successful tasks is only kept in grouped mode.
+func (p *Playbook) collectOutput(task *bobtask.Task, output *bytes.Buffer, failed bool) {
+	if output == nil || output.Len() == 0 {
+		return
+	}
+	if !failed && p.outputMode != OutputGrouped {
+		return
+	}
+
+	p.outputMu.Lock()
+	defer p.outputMu.Unlock()
+	p.outputBlocks = append(p.outputBlocks, outputBlock{
+		task:   task,
+		output: output.Bytes(),
+		failed: failed,
+	})
+}
+
+// sortedOutput returns the collected output blocks, the blocks
+// of failed tasks first, each in the order the tasks finished.
+func (p *Playbook) sortedOutput() []outputBlock {
+	p.outputMu.Lock()
+	defer p.outputMu.Unlock()
+
+	blocks := make([]outputBlock, len(p.outputBlocks))
+	copy(blocks, p.outputBlocks)
+	sort.SliceStable(blocks, func(i, j int) bool {
+		return blocks[i].failed && !blocks[j].failed
+	})
+	return blocks
+}
+
+// printOutput prints and drops the collected output blocks.
+func (p *Playbook) printOutput() {
+	for _, block := range p.sortedOutput() {
+		err := block.task.PrintOutput(p.namePad, block.output)
+		if err != nil {
+			boblog.Log.Error(err, fmt.Sprintf("Unable to print the output of task %s", block.task.Name()))
+		}
+	}
+
+	p.outputMu.Lock()
+	defer p.outputMu.Unlock()
+	p.outputBlocks = nil
+}
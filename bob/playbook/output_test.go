@@ -0,0 +1,76 @@
+package playbook
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/benchkram/bob/bobtask"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseOutputMode(t *testing.T) {
+	for _, mode := range []OutputMode{OutputStream, OutputGrouped, OutputFailedOnly} {
+		parsed, err := ParseOutputMode(string(mode))
+		assert.Nil(t, err)
+		assert.Equal(t, mode, parsed)
+	}
+
+	_, err := ParseOutputMode("quiet")
+	assert.ErrorIs(t, err, ErrInvalidOutputMode)
+}
+
+func TestOutputBuffer(t *testing.T) {
+	assert.Nil(t, New(nil).newOutputBuffer(), "output is streamed by default")
+	assert.Nil(t, New(nil, WithOutputMode(OutputStream)).newOutputBuffer())
+	assert.Nil(t, outputWriter(nil), "a nil buffer must not be passed as writer")
+
+	assert.NotNil(t, New(nil, WithOutputMode(OutputGrouped)).newOutputBuffer())
+	assert.NotNil(t, New(nil, WithOutputMode(OutputFailedOnly)).newOutputBuffer())
+}
+
+func TestCollectOutput(t *testing.T) {
+	collect := func(p *Playbook) []string {
+		for _, task := range []struct {
+			name   string
+			failed bool
+		}{
+			{"first", false},
+			{"second", true},
+			{"third", false},
+			{"fourth", true},
+		} {
+			bt := bobtask.Make()
+			bt.SetName(task.name)
+
+			output := p.newOutputBuffer()
+			if output != nil {
+				output.WriteString(task.name + "\n")
+			}
+			p.collectOutput(&bt, output, task.failed)
+		}
+
+		names := []string{}
+		for _, block := range p.sortedOutput() {
+			assert.Equal(t, block.task.Name()+"\n", string(block.output))
+			names = append(names, block.task.Name())
+		}
+		return names
+	}
+
+	assert.Empty(t, collect(New(nil, WithOutputMode(OutputStream))))
+	assert.Equal(t, []string{"second", "fourth", "first", "third"}, collect(New(nil, WithOutputMode(OutputGrouped))),
+		"failed tasks are printed first")
+	assert.Equal(t, []string{"second", "fourth"}, collect(New(nil, WithOutputMode(OutputFailedOnly))),
+		"the output of successful tasks is hidden")
+
+	// tasks without output are not printed
+	p := New(nil, WithOutputMode(OutputGrouped))
+	bt := bobtask.Make()
+	p.collectOutput(&bt, &bytes.Buffer{}, true)
+	assert.Empty(t, p.sortedOutput())
+
+	// printed output is dropped
+	p.collectOutput(&bt, bytes.NewBufferString("output\n"), true)
+	p.printOutput()
+	assert.Empty(t, p.sortedOutput())
+}
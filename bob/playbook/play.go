@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/benchkram/bob/pkg/boberror"
+	"github.com/benchkram/bob/pkg/sliceutil"
 	"github.com/benchkram/bob/pkg/usererror"
 )
 
@@ -26,13 +27,12 @@ func (p *Playbook) play() error {
 		p.start = time.Now()
 	}
 
-	// Walk the task chain and determine the next build task. Send it to the task channel.
-	// Returns `taskQueued` when a task has been send to the taskChannel.
+	// Walk the task chain and collect the tasks ready to be build.
 	// Returns `taskFailed` when a task has failed.
-	// Once it returns `nil` the playbook is done with it's work.
-	var taskQueued = fmt.Errorf("task queued")
+	// Once no task is ready and none is running the playbook is done with it's work.
 	var taskFailed = fmt.Errorf("task failed")
-	err := p.Tasks.walk(p.root, func(taskname string, task *Status, err error) error {
+	var ready []string
+	err := p.Tasks.walkRoots(p.roots, func(taskname string, task *Status, err error) error {
 		if err != nil {
 			return err
 		}
@@ -50,18 +50,25 @@ func (p *Playbook) play() error {
 				}
 
 				state := t.State()
-				if state != StateCompleted && state != StateNoRebuildRequired {
+				if state != StateCompleted && state != StateNoRebuildRequired && state != StateSkipped {
 					// A dependent task is not completed.
 					// So this task is not yet ready to run.
 					return nil
 				}
 			}
 		case StateFailed:
+			if p.keepGoing {
+				// Dependents are canceled,
+				// independent tasks can still run.
+				return nil
+			}
 			return taskFailed
 		case StateCanceled:
 			return nil
 		case StateNoRebuildRequired:
 			return nil
+		case StateSkipped:
+			return nil
 		case StateCompleted:
 			return nil
 		case StateRunning:
@@ -69,28 +76,39 @@ func (p *Playbook) play() error {
 		default:
 		}
 
-		// fmt.Printf("sending task %s to channel\n", task.Task.Name())
-		// setting the task start time before passing it to channel
-		task.SetStart(time.Now())
-		// TODO: for async assure to handle send to a closed channel.
-		_ = p.setTaskState(task.Name(), StateRunning, nil)
-		p.taskChannel <- task.Task
-		return taskQueued
+		// tasks can be reached through multiple parents.
+		if !sliceutil.Contains(ready, taskname) {
+			ready = append(ready, taskname)
+		}
+		return nil
 	})
 
-	// taskQueued => return nil (happy path)
 	// taskFailed => return PlaybookFailed (ErrFailed)
 	// default    => return err
 	if err != nil {
-		if errors.Is(err, taskQueued) {
-			return nil
-		}
 		if errors.Is(err, taskFailed) {
 			return ErrFailed
 		}
 		return err
 	}
 
+	// Tasks waiting for a resource are started once a task
+	// using the resource is done, which triggers another play.
+	ready = p.filterResourcesAvailable(ready)
+
+	// When more tasks are ready than workers are available, tasks
+	// on the longest critical path are started first.
+	if len(ready) > 0 {
+		task := p.Tasks[p.nextTask(ready)]
+
+		// setting the task start time before passing it to channel
+		task.SetStart(time.Now())
+		// TODO: for async assure to handle send to a closed channel.
+		_ = p.setTaskState(task.Name(), StateRunning, nil)
+		p.taskChannel <- task.Task
+		return nil
+	}
+
 	// Avoid finishing the playbook before all task are done running
 	if p.numRunningTasks() > 0 {
 		return nil
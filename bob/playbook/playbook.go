@@ -32,8 +32,9 @@ type Playbook struct {
 	// errorChannel to transport errors to the caller
 	errorChannel chan error
 
-	// root task
-	root string
+	// roots are the tasks the playbook is started with.
+	// Common dependencies are only build once.
+	roots []string
 
 	Tasks StatusMap
 
@@ -74,15 +75,54 @@ type Playbook struct {
 
 	// enablePull allows pulling artifacts from remote store
 	enablePull bool
+
+	// keepGoing continues building independent tasks
+	// after a task failed. Only tasks depending on the
+	// failed task are canceled.
+	keepGoing bool
+
+	// outputMode determines how the output of tasks is printed.
+	// Default: stream.
+	outputMode OutputMode
+
+	// outputBlocks are the collected outputs of tasks
+	// printed after the build, unless output is streamed.
+	outputBlocks []outputBlock
+	outputMu     sync.Mutex
+
+	// replayLogs prints the stored output
+	// of tasks which don't need a rebuild.
+	replayLogs bool
+
+	// subscribers receive an event on each task state change.
+	subscribers subscribers
+
+	// traceReport is the path a chrome trace
+	// of the build is written to, if set.
+	traceReport string
+
+	// junitReport is the path a junit report
+	// of the build is written to, if set.
+	junitReport string
+
+	// durations are the historical execution times of tasks.
+	// Used to start tasks on the critical path first.
+	durations map[string]time.Duration
+
+	// criticalPaths caches the critical path of each task.
+	criticalPaths map[string]time.Duration
+
+	// resources maps the name of a resource pool to its capacity.
+	resources map[string]int
 }
 
-func New(root string, opts ...Option) *Playbook {
+func New(roots []string, opts ...Option) *Playbook {
 	p := &Playbook{
 		errorChannel:  make(chan error),
 		Tasks:         make(StatusMap),
 		doneChannel:   make(chan struct{}),
 		enableCaching: true,
-		root:          root,
+		roots:         roots,
 
 		maxParallel: runtime.NumCPU(),
 
@@ -120,17 +160,11 @@ const (
 
 func (p *Playbook) numRunningTasks() int {
 	var parallel int
-	_ = p.Tasks.walk(p.root, func(taskname string, task *Status, err error) error {
-		if err != nil {
-			return err
-		}
-
+	for _, task := range p.Tasks {
 		if task.State() == StateRunning {
 			parallel++
 		}
-
-		return nil
-	})
+	}
 	return parallel
 }
 
@@ -179,6 +213,7 @@ func (p *Playbook) TaskCompleted(taskname string) (err error) {
 
 	buildInfo, err := p.computeBuildinfo(taskname)
 	errz.Fatal(err)
+	task.SetInputHash(hash.In(buildInfo.Meta.InputHash))
 
 	// Store buildinfo
 	err = p.storeBuildInfo(taskname, buildInfo)
@@ -222,6 +257,24 @@ func (p *Playbook) TaskNoRebuildRequired(taskname string) (err error) {
 	return nil
 }
 
+// TaskSkipped sets a task's state to indicate that
+// it was skipped as its condition is false.
+func (p *Playbook) TaskSkipped(taskname string) (err error) {
+	defer errz.Recover(&err)
+
+	err = p.setTaskState(taskname, StateSkipped, nil)
+	errz.Fatal(err)
+
+	err = p.play()
+	if err != nil {
+		if !errors.Is(err, ErrDone) {
+			errz.Fatal(err)
+		}
+	}
+
+	return nil
+}
+
 // TaskFailed sets a task to failed
 func (p *Playbook) TaskFailed(taskname string, taskErr error) (err error) {
 	defer errz.Recover(&err)
@@ -229,6 +282,11 @@ func (p *Playbook) TaskFailed(taskname string, taskErr error) (err error) {
 	err = p.setTaskState(taskname, StateFailed, taskErr)
 	errz.Fatal(err)
 
+	if p.keepGoing {
+		err = p.cancelDependents(taskname)
+		errz.Fatal(err)
+	}
+
 	// p.errorChannel <- fmt.Errorf("Task %s failed", taskname)
 
 	// give the playbook the chance to set
@@ -250,6 +308,63 @@ func (p *Playbook) TaskCanceled(taskname string) (err error) {
 	return nil
 }
 
+// buildCanceled sets a task and all pending tasks to canceled,
+// no further tasks are started once a build is canceled.
+func (p *Playbook) buildCanceled(taskname string) (err error) {
+	defer errz.Recover(&err)
+
+	err = p.setTaskState(taskname, StateCanceled, nil)
+	errz.Fatal(err)
+
+	for name, task := range p.Tasks {
+		if task.State() != StatePending {
+			continue
+		}
+		err = p.setTaskState(name, StateCanceled, nil)
+		errz.Fatal(err)
+	}
+
+	// give the playbook the chance to set
+	// the state to done.
+	_ = p.play()
+
+	return nil
+}
+
+// cancelDependents sets all pending tasks depending
+// (directly or transitively) on taskname to canceled.
+func (p *Playbook) cancelDependents(taskname string) error {
+	var found = fmt.Errorf("found")
+	for name, task := range p.Tasks {
+		if name == taskname || task.State() != StatePending {
+			continue
+		}
+
+		err := p.Tasks.walk(name, func(tn string, _ *Status, err error) error {
+			if err != nil {
+				return err
+			}
+			if tn == taskname {
+				return found
+			}
+			return nil
+		})
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, found) {
+			return err
+		}
+
+		err = p.setTaskState(name, StateCanceled, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (p *Playbook) List() (err error) {
 	defer errz.Recover(&err)
 
@@ -293,10 +408,12 @@ func (p *Playbook) setTaskState(taskname string, state State, taskError error) e
 
 	task.SetState(state, taskError)
 	switch state {
-	case StateCompleted, StateCanceled, StateNoRebuildRequired, StateFailed:
+	case StateCompleted, StateCanceled, StateNoRebuildRequired, StateSkipped, StateFailed:
 		task.SetEnd(time.Now())
 	}
 
+	p.emit(eventType(state), task)
+
 	return nil
 }
 
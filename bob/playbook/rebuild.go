@@ -79,8 +79,9 @@ func (p *Playbook) didChildTaskChange(taskname string, namePad int, coloredName
 			return nil
 		}
 
-		// Check if child task changed
-		if t.State() != StateNoRebuildRequired {
+		// Check if child task changed,
+		// skipped tasks didn't change anything.
+		if t.State() != StateNoRebuildRequired && t.State() != StateSkipped {
 			return Done
 		}
 
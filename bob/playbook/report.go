@@ -0,0 +1,231 @@
+package playbook
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+)
+
+// writeReports writes the reports requested
+// by the playbook options.
+func (p *Playbook) writeReports() error {
+	if p.traceReport != "" {
+		err := writeReportFile(p.traceReport, p.WriteTrace)
+		if err != nil {
+			return fmt.Errorf("failed to write trace report: %w", err)
+		}
+	}
+	if p.junitReport != "" {
+		err := writeReportFile(p.junitReport, p.WriteJUnit)
+		if err != nil {
+			return fmt.Errorf("failed to write junit report: %w", err)
+		}
+	}
+	return nil
+}
+
+func writeReportFile(path string, write func(io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	err = write(f)
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// sortedTasks returns the tasks of the playbook ordered by name.
+func (p *Playbook) sortedTasks() []*Status {
+	tasks := make([]*Status, 0, len(p.Tasks))
+	for _, t := range p.Tasks {
+		tasks = append(tasks, t)
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].Name() < tasks[j].Name()
+	})
+	return tasks
+}
+
+// reportDuration of a task from the time a worker started processing it,
+// excluding the time waiting for a worker. Cached, skipped tasks and tasks
+// never processed by a worker are reported with zero duration.
+func reportDuration(t *Status) time.Duration {
+	if t.State() == StateNoRebuildRequired || t.State() == StateSkipped || t.WorkerID() == 0 {
+		return 0
+	}
+	if d := t.End().Sub(t.WorkerStart()); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// traceEvent is an event of the chrome trace event format.
+// https://docs.google.com/document/d/1CvAClvFfyA5R-PhYUmn5OOQtYMH4h6I0nSsKchNAySU
+type traceEvent struct {
+	Name  string            `json:"name"`
+	Cat   string            `json:"cat,omitempty"`
+	Phase string            `json:"ph"`
+	TS    int64             `json:"ts"`
+	Dur   int64             `json:"dur"`
+	PID   int               `json:"pid"`
+	TID   int               `json:"tid"`
+	Args  map[string]string `json:"args,omitempty"`
+}
+
+type trace struct {
+	TraceEvents     []traceEvent `json:"traceEvents"`
+	DisplayTimeUnit string       `json:"displayTimeUnit"`
+}
+
+// WriteTrace writes the timing of all processed tasks in the chrome
+// trace event format, viewable in chrome://tracing or Perfetto.
+// Each worker of the playbook is shown as a separate lane.
+func (p *Playbook) WriteTrace(w io.Writer) error {
+	tr := trace{
+		TraceEvents:     []traceEvent{},
+		DisplayTimeUnit: "ms",
+	}
+
+	workers := make(map[int]bool)
+	for _, t := range p.sortedTasks() {
+		workerID := t.WorkerID()
+		if workerID == 0 {
+			// never processed
+			continue
+		}
+		workers[workerID] = true
+
+		state := t.State()
+		args := map[string]string{"state": state.Short()}
+		if cause := t.RebuildCause(); cause != "" {
+			args["rebuild_cause"] = cause.String()
+		}
+
+		tr.TraceEvents = append(tr.TraceEvents, traceEvent{
+			Name:  t.Name(),
+			Cat:   "task",
+			Phase: "X",
+			TS:    t.WorkerStart().Sub(p.start).Microseconds(),
+			Dur:   reportDuration(t).Microseconds(),
+			PID:   1,
+			TID:   workerID,
+			Args:  args,
+		})
+	}
+
+	workerIDs := make([]int, 0, len(workers))
+	for workerID := range workers {
+		workerIDs = append(workerIDs, workerID)
+	}
+	sort.Ints(workerIDs)
+	for _, workerID := range workerIDs {
+		tr.TraceEvents = append(tr.TraceEvents, traceEvent{
+			Name:  "thread_name",
+			Phase: "M",
+			PID:   1,
+			TID:   workerID,
+			Args:  map[string]string{"name": fmt.Sprintf("worker %d", workerID)},
+		})
+	}
+
+	return json.NewEncoder(w).Encode(tr)
+}
+
+type junitTestsuites struct {
+	XMLName    xml.Name         `xml:"testsuites"`
+	Testsuites []junitTestsuite `xml:"testsuite"`
+}
+
+type junitTestsuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	Testcases []junitTestcase `xml:"testcase"`
+}
+
+type junitTestcase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func junitSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+// WriteJUnit writes the result of each task as a testcase in the JUnit XML format.
+// Cached tasks and tasks which did not run are reported as skipped.
+func (p *Playbook) WriteJUnit(w io.Writer) error {
+	suite := junitTestsuite{
+		Name:      "bob",
+		Time:      junitSeconds(p.ExecutionTime()),
+		Timestamp: p.start.Format("2006-01-02T15:04:05"),
+	}
+
+	for _, t := range p.sortedTasks() {
+		tc := junitTestcase{
+			Name:      t.Name(),
+			Classname: t.Project(),
+			Time:      junitSeconds(reportDuration(t)),
+		}
+
+		switch t.State() {
+		case StateFailed:
+			msg := "task failed"
+			if t.Error != nil {
+				msg = t.Error.Error()
+			}
+			tc.Failure = &junitMessage{Message: msg, Text: msg}
+			suite.Failures++
+		case StateNoRebuildRequired:
+			tc.Skipped = &junitMessage{Message: "cached"}
+			suite.Skipped++
+		case StateSkipped:
+			tc.Skipped = &junitMessage{Message: "condition is false"}
+			suite.Skipped++
+		case StateCanceled:
+			tc.Skipped = &junitMessage{Message: "canceled"}
+			suite.Skipped++
+		case StatePending:
+			tc.Skipped = &junitMessage{Message: "not run"}
+			suite.Skipped++
+		}
+
+		suite.Testcases = append(suite.Testcases, tc)
+	}
+	suite.Tests = len(suite.Testcases)
+
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	err = enc.Encode(junitTestsuites{Testsuites: []junitTestsuite{suite}})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "\n")
+	return err
+}
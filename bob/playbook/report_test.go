@@ -0,0 +1,47 @@
+package playbook
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/benchkram/bob/bobtask"
+)
+
+func TestReportsExcludeWaitingForWorker(t *testing.T) {
+	p := New([]string{"build"})
+	start := time.Now()
+	p.start = start
+
+	task := bobtask.Make()
+	task.SetName("build")
+	status := NewStatus(&task)
+	p.Tasks["build"] = status
+
+	// queued at once, waiting a second for a worker
+	status.SetStart(start)
+	status.SetWorkerID(1)
+	status.SetWorkerStart(start.Add(time.Second))
+	status.SetEnd(start.Add(3 * time.Second))
+	status.SetState(StateCompleted, nil)
+
+	var trace bytes.Buffer
+	assert.Nil(t, p.WriteTrace(&trace))
+	var tr struct {
+		TraceEvents []traceEvent `json:"traceEvents"`
+	}
+	assert.Nil(t, json.Unmarshal(trace.Bytes(), &tr))
+	assert.Equal(t, "build", tr.TraceEvents[0].Name)
+	assert.Equal(t, time.Second.Microseconds(), tr.TraceEvents[0].TS)
+	assert.Equal(t, (2 * time.Second).Microseconds(), tr.TraceEvents[0].Dur)
+
+	var junit bytes.Buffer
+	assert.Nil(t, p.WriteJUnit(&junit))
+	var suites junitTestsuites
+	assert.Nil(t, xml.Unmarshal(junit.Bytes(), &suites))
+	assert.Equal(t, "2.000", suites.Testsuites[0].Testcases[0].Time)
+}
@@ -0,0 +1,65 @@
+package playbook
+
+// filterResourcesAvailable returns the tasks which can be started
+// without exceeding the capacity of a resource pool.
+//
+// Must be called with the playMutex held.
+func (p *Playbook) filterResourcesAvailable(tasknames []string) []string {
+	available := []string{}
+	for _, taskname := range tasknames {
+		if p.resourcesAvailable(p.Tasks[taskname]) {
+			available = append(available, taskname)
+		}
+	}
+	return available
+}
+
+// resourcesAvailable checks if the resources used by a task are
+// available considering all tasks currently running.
+// Exclusive tasks only run when no other task is running and
+// block other tasks from being started.
+//
+// As a task is only blocked by running tasks the playbook
+// can not deadlock waiting for a resource.
+func (p *Playbook) resourcesAvailable(task *Status) bool {
+	var running []*Status
+	for _, t := range p.Tasks {
+		if t.State() == StateRunning {
+			running = append(running, t)
+		}
+	}
+
+	if len(running) == 0 {
+		return true
+	}
+
+	if task.Exclusive {
+		return false
+	}
+
+	inUse := make(map[string]int)
+	for _, t := range running {
+		if t.Exclusive {
+			return false
+		}
+		for _, resource := range t.Uses {
+			inUse[resource]++
+		}
+	}
+
+	for _, resource := range task.Uses {
+		capacity, ok := p.resources[resource]
+		if !ok {
+			// undeclared resources are rejected on bobfile verification.
+			continue
+		}
+		if capacity < 1 {
+			capacity = 1
+		}
+		if inUse[resource] >= capacity {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,84 @@
+package playbook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/benchkram/bob/bobtask"
+)
+
+// newResourcesPlaybook creates a playbook for
+//
+//	all -> a
+//	all -> b
+func newResourcesPlaybook(a, b bobtask.Task, opts ...Option) *Playbook {
+	p := New([]string{"all"}, opts...)
+
+	all := bobtask.Make()
+	all.SetName("all")
+	all.DependsOn = []string{"a", "b"}
+	p.Tasks["all"] = NewStatus(&all)
+
+	a.SetName("a")
+	p.Tasks["a"] = NewStatus(&a)
+	b.SetName("b")
+	p.Tasks["b"] = NewStatus(&b)
+
+	return p
+}
+
+func TestResourcesLimitParallelism(t *testing.T) {
+	a := bobtask.Make()
+	a.Uses = []string{"docker"}
+	b := bobtask.Make()
+	b.Uses = []string{"docker"}
+
+	p := newResourcesPlaybook(a, b, WithResources(map[string]int{"docker": 1}))
+
+	assert.Nil(t, p.Play())
+	first := <-p.TaskChannel()
+
+	// the resource is in use, no other task is started.
+	assert.Nil(t, p.Play())
+	assert.Len(t, p.TaskChannel(), 0)
+
+	assert.Nil(t, p.setTaskState(first.Name(), StateCompleted, nil))
+	assert.Nil(t, p.Play())
+	second := <-p.TaskChannel()
+	assert.NotEqual(t, first.Name(), second.Name())
+}
+
+func TestResourcesCapacity(t *testing.T) {
+	a := bobtask.Make()
+	a.Uses = []string{"docker"}
+	b := bobtask.Make()
+	b.Uses = []string{"docker"}
+
+	p := newResourcesPlaybook(a, b, WithResources(map[string]int{"docker": 2}))
+
+	assert.Nil(t, p.Play())
+	assert.Nil(t, p.Play())
+	assert.Len(t, p.TaskChannel(), 2)
+}
+
+func TestExclusiveTask(t *testing.T) {
+	a := bobtask.Make()
+	a.Exclusive = true
+	b := bobtask.Make()
+
+	p := newResourcesPlaybook(a, b)
+
+	assert.Nil(t, p.Play())
+	first := <-p.TaskChannel()
+
+	// no task runs in parallel to an exclusive task
+	// and an exclusive task waits for all others.
+	assert.Nil(t, p.Play())
+	assert.Len(t, p.TaskChannel(), 0)
+
+	assert.Nil(t, p.setTaskState(first.Name(), StateCompleted, nil))
+	assert.Nil(t, p.Play())
+	second := <-p.TaskChannel()
+	assert.NotEqual(t, first.Name(), second.Name())
+}
@@ -14,6 +14,8 @@ func (s *State) Summary() string {
 		return aurora.Green("✔").Bold().String() + "       "
 	case StateNoRebuildRequired:
 		return aurora.Green("cached").String() + "  "
+	case StateSkipped:
+		return aurora.Faint("skipped").String() + " "
 	case StateFailed:
 		return aurora.Red("failed").String() + "  "
 	case StateCanceled:
@@ -31,6 +33,8 @@ func (s *State) Short() string {
 		return "done"
 	case StateNoRebuildRequired:
 		return "cached"
+	case StateSkipped:
+		return "skipped"
 	case StateFailed:
 		return "failed"
 	case StateCanceled:
@@ -44,6 +48,7 @@ const (
 	StatePending           State = "PENDING"
 	StateCompleted         State = "COMPLETED"
 	StateNoRebuildRequired State = "CACHED"
+	StateSkipped           State = "SKIPPED"
 	StateFailed            State = "FAILED"
 	StateRunning           State = "RUNNING"
 	StateCanceled          State = "CANCELED"
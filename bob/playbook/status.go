@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/benchkram/bob/bobtask"
+	"github.com/benchkram/bob/bobtask/hash"
 )
 
 // Status holds the state of a task
@@ -20,6 +21,26 @@ type Status struct {
 	endMu   sync.RWMutex
 	end     time.Time
 
+	// rebuildCause and artifactSync are
+	// determined while building the task.
+	buildMu      sync.RWMutex
+	rebuildCause RebuildCause
+	artifactSync *ArtifactSync
+	// artifactSource is set when the targets
+	// were extracted from an artifact.
+	artifactSource ArtifactSource
+	// workerID of the worker processing the task, 0 if not processed.
+	workerID int
+	// workerStart is the time the worker started processing the task,
+	// start is the time the task was queued.
+	workerStart time.Time
+	// runDuration is the execution time of the
+	// task's commands, without waiting for a worker.
+	runDuration time.Duration
+	// inputHash is recorded once the worker computed it,
+	// to be read without touching the task.
+	inputHash hash.In
+
 	Error error
 }
 
@@ -78,3 +99,98 @@ func (ts *Status) SetEnd(end time.Time) {
 	defer ts.endMu.Unlock()
 	ts.end = end
 }
+
+func (ts *Status) RebuildCause() RebuildCause {
+	ts.buildMu.RLock()
+	defer ts.buildMu.RUnlock()
+	return ts.rebuildCause
+}
+
+func (ts *Status) SetRebuildCause(cause RebuildCause) {
+	ts.buildMu.Lock()
+	defer ts.buildMu.Unlock()
+	ts.rebuildCause = cause
+}
+
+// ArtifactSync returns the result of the last artifact
+// sync with the remote store, nil if none happened.
+func (ts *Status) ArtifactSync() *ArtifactSync {
+	ts.buildMu.RLock()
+	defer ts.buildMu.RUnlock()
+	return ts.artifactSync
+}
+
+func (ts *Status) SetArtifactSync(sync *ArtifactSync) {
+	ts.buildMu.Lock()
+	defer ts.buildMu.Unlock()
+	ts.artifactSync = sync
+}
+
+// ArtifactSource returns from where the targets of
+// the task were loaded, empty if the task was not
+// loaded from an artifact.
+func (ts *Status) ArtifactSource() ArtifactSource {
+	ts.buildMu.RLock()
+	defer ts.buildMu.RUnlock()
+	return ts.artifactSource
+}
+
+func (ts *Status) SetArtifactSource(source ArtifactSource) {
+	ts.buildMu.Lock()
+	defer ts.buildMu.Unlock()
+	ts.artifactSource = source
+}
+
+func (ts *Status) WorkerID() int {
+	ts.buildMu.RLock()
+	defer ts.buildMu.RUnlock()
+	return ts.workerID
+}
+
+func (ts *Status) SetWorkerID(id int) {
+	ts.buildMu.Lock()
+	defer ts.buildMu.Unlock()
+	ts.workerID = id
+}
+
+// WorkerStart returns the time a worker started processing
+// the task, zero if not processed.
+func (ts *Status) WorkerStart() time.Time {
+	ts.buildMu.RLock()
+	defer ts.buildMu.RUnlock()
+	return ts.workerStart
+}
+
+func (ts *Status) SetWorkerStart(start time.Time) {
+	ts.buildMu.Lock()
+	defer ts.buildMu.Unlock()
+	ts.workerStart = start
+}
+
+// InputHash returns the input hash recorded
+// while building the task, empty if unknown.
+func (ts *Status) InputHash() hash.In {
+	ts.buildMu.RLock()
+	defer ts.buildMu.RUnlock()
+	return ts.inputHash
+}
+
+func (ts *Status) SetInputHash(h hash.In) {
+	ts.buildMu.Lock()
+	defer ts.buildMu.Unlock()
+	ts.inputHash = h
+}
+
+// RunDuration returns the execution time of the last
+// run of the task's commands, zero if not run.
+func (ts *Status) RunDuration() time.Duration {
+	ts.buildMu.RLock()
+	defer ts.buildMu.RUnlock()
+	return ts.runDuration
+}
+
+func (ts *Status) SetRunDuration(d time.Duration) {
+	ts.buildMu.Lock()
+	defer ts.buildMu.Unlock()
+	ts.runDuration = d
+}
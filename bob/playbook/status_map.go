@@ -27,3 +27,46 @@ func (tsm StatusMap) walk(root string, fn func(taskname string, _ *Status, _ err
 
 	return nil
 }
+
+// walkRoots walks the task tree of each root.
+// Stops on the first error.
+func (tsm StatusMap) walkRoots(roots []string, fn func(taskname string, _ *Status, _ error) error) error {
+	for _, root := range roots {
+		err := tsm.walk(root, fn)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// postOrder returns the tasknames of the task trees starting at roots.
+// Dependencies are listed before the tasks depending on them,
+// each task is only listed once.
+func (tsm StatusMap) postOrder(roots []string) []string {
+	var ordered []string
+	visited := make(map[string]bool, len(tsm))
+
+	var visit func(taskname string)
+	visit = func(taskname string) {
+		if visited[taskname] {
+			return
+		}
+		visited[taskname] = true
+
+		task, ok := tsm[taskname]
+		if !ok {
+			return
+		}
+		for _, dependentTaskName := range task.Task.DependsOn {
+			visit(dependentTaskName)
+		}
+		ordered = append(ordered, taskname)
+	}
+
+	for _, root := range roots {
+		visit(root)
+	}
+
+	return ordered
+}
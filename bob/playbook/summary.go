@@ -2,6 +2,7 @@ package playbook
 
 import (
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/benchkram/bob/bobtask"
@@ -27,16 +28,43 @@ func (p *Playbook) summary(processedTasks []*bobtask.Task) {
 
 		execTime := ""
 		status := stat.State()
-		if status != StateNoRebuildRequired {
+		if status != StateNoRebuildRequired && status != StateSkipped {
 			execTime = fmt.Sprintf("\t(%s)", displayDuration(stat.ExecutionTime()))
 		}
 
 		taskName := t.Name()
 		boblog.Log.V(1).Info(fmt.Sprintf("  %-*s\t%s%s", p.namePad, taskName, status.Summary(), execTime))
 	}
+
+	// Tasks canceled due to a failed dependency
+	// never reached a worker.
+	for _, taskName := range p.canceledTasks(processedTasks) {
+		status := StateCanceled
+		boblog.Log.V(1).Info(fmt.Sprintf("  %-*s\t%s", p.namePad, taskName, status.Summary()))
+	}
 	boblog.Log.V(1).Info("")
 }
 
+// canceledTasks returns the names of canceled
+// tasks which are not in processedTasks.
+func (p *Playbook) canceledTasks(processedTasks []*bobtask.Task) []string {
+	processed := make(map[string]bool, len(processedTasks))
+	for _, t := range processedTasks {
+		processed[t.Name()] = true
+	}
+
+	var canceled []string
+	for name, t := range p.Tasks {
+		if processed[name] || t.State() != StateCanceled {
+			continue
+		}
+		canceled = append(canceled, name)
+	}
+	sort.Strings(canceled)
+
+	return canceled
+}
+
 func displayDuration(d time.Duration) string {
 	if d.Minutes() > 1 {
 		return fmt.Sprintf("%.1fm", float64(d)/float64(time.Minute))
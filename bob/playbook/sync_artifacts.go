@@ -14,46 +14,90 @@ import (
 // TaskKey is key for context values passed to client for upload/download output formatting
 type TaskKey string
 
-func (p *Playbook) downloadArtifact(ctx context.Context, a hash.In, taskName string, ignoreLocal bool) {
+type SyncDirection string
+
+const (
+	SyncPull SyncDirection = "pull"
+	SyncPush SyncDirection = "push"
+)
+
+type SyncResult string
+
+const (
+	SyncResultSynced        SyncResult = "synced"
+	SyncResultAlreadyExists SyncResult = "already-exists"
+	SyncResultNotFound      SyncResult = "not-found"
+	SyncResultFailed        SyncResult = "failed"
+)
+
+// ArtifactSync is the result of syncing an
+// artifact between the local and the remote store.
+type ArtifactSync struct {
+	ArtifactID string        `json:"id"`
+	Direction  SyncDirection `json:"direction"`
+	Result     SyncResult    `json:"result"`
+	Error      string        `json:"error,omitempty"`
+}
+
+// downloadArtifact syncs the artifact from the remote store, returns nil if pulling is disabled.
+func (p *Playbook) downloadArtifact(ctx context.Context, a hash.In, taskName string, ignoreLocal bool) *ArtifactSync {
 	if p.enablePull && p.enableCaching && p.remoteStore != nil && p.localStore != nil {
 		description := fmt.Sprintf("%-*s\t  %s", p.namePad, taskName, aurora.Faint("pulling artifact "+a.String()))
 		ctx = context.WithValue(ctx, TaskKey("description"), description)
-		syncFromRemoteToLocal(ctx, p.remoteStore, p.localStore, a, ignoreLocal)
+		return syncFromRemoteToLocal(ctx, p.remoteStore, p.localStore, a, ignoreLocal)
 	}
+	return nil
 }
 
-func (p *Playbook) pushArtifact(ctx context.Context, a hash.In, taskName string) {
+// pushArtifact syncs the artifact to the remote store, returns nil if pushing is not possible.
+func (p *Playbook) pushArtifact(ctx context.Context, a hash.In, taskName string) *ArtifactSync {
 	if p.enableCaching && p.remoteStore != nil && p.localStore != nil {
 		description := fmt.Sprintf("  %-*s\t%s", p.namePad, taskName, aurora.Faint("pushing artifact "+a.String()))
 		ctx = context.WithValue(ctx, TaskKey("description"), description)
-		syncFromLocalToRemote(ctx, p.localStore, p.remoteStore, a)
+		return syncFromLocalToRemote(ctx, p.localStore, p.remoteStore, a)
 	}
+	return nil
 }
 
 // syncFromRemoteToLocal syncs the artifact from the remote store to the local store.
 // if ignoreAlreadyExists is true it will ignore local artifact and perform a fresh download
-func syncFromRemoteToLocal(ctx context.Context, remote store.Store, local store.Store, a hash.In, ignoreAlreadyExists bool) {
+func syncFromRemoteToLocal(ctx context.Context, remote store.Store, local store.Store, a hash.In, ignoreAlreadyExists bool) *ArtifactSync {
+	result := &ArtifactSync{ArtifactID: a.String(), Direction: SyncPull, Result: SyncResultSynced}
+
 	err := store.Sync(ctx, remote, local, a.String(), ignoreAlreadyExists)
 	if errors.Is(err, store.ErrArtifactAlreadyExists) {
 		boblog.Log.V(5).Info(fmt.Sprintf("artifact already exists locally [artifactId: %s]. skipping...", a.String()))
+		result.Result = SyncResultAlreadyExists
+		return result
 	} else if errors.Is(err, store.ErrArtifactNotFoundinSrc) {
 		boblog.Log.V(5).Info(fmt.Sprintf("failed to sync from remote to local [artifactId: %s]", a.String()))
+		result.Result = SyncResultNotFound
+		return result
 	} else if err != nil {
 		boblog.Log.V(5).Error(err, fmt.Sprintf("failed to sync from remote to local [artifactId: %s]", a.String()))
+		result.Result = SyncResultFailed
+		result.Error = err.Error()
+		return result
 	}
 
 	boblog.Log.V(5).Info(fmt.Sprintf("synced from remote to local [artifactId: %s]", a.String()))
+	return result
 }
 
 // syncFromLocalToRemote syncs the artifact from the local store to the remote store.
-func syncFromLocalToRemote(ctx context.Context, local store.Store, remote store.Store, a hash.In) {
+func syncFromLocalToRemote(ctx context.Context, local store.Store, remote store.Store, a hash.In) *ArtifactSync {
+	result := &ArtifactSync{ArtifactID: a.String(), Direction: SyncPush, Result: SyncResultSynced}
+
 	err := store.Sync(ctx, local, remote, a.String(), false)
 	if errors.Is(err, store.ErrArtifactAlreadyExists) {
 		boblog.Log.V(5).Info(fmt.Sprintf("artifact already exists on the remote [artifactId: %s]. skipping...", a.String()))
-		return
+		result.Result = SyncResultAlreadyExists
+		return result
 	} else if err != nil {
 		boblog.Log.V(5).Error(err, fmt.Sprintf("failed to sync from local to remote [artifactId: %s]", a.String()))
-		return
+		result.Result = SyncResultFailed
+		result.Error = err.Error()
+		return result
 	}
 
 	// wait for the remote store to finish uploading this artifact. can be moved outside the for loop, but then
@@ -61,7 +105,10 @@ func syncFromLocalToRemote(ctx context.Context, local store.Store, remote store.
 	err = remote.Done()
 	if err != nil {
 		boblog.Log.V(5).Error(err, fmt.Sprintf("failed to sync from local to remote [artifactId: %s]", a.String()))
-		return
+		result.Result = SyncResultFailed
+		result.Error = err.Error()
+		return result
 	}
 	boblog.Log.V(5).Info(fmt.Sprintf("synced from local to remote [artifactId: %s]", a.String()))
+	return result
 }
@@ -268,7 +268,7 @@ func createPlaygroundBobfile(dir string, overwrite bool, projectName string) (er
 	bobfile.Variables["helloworld"] = "Hello World!"
 
 	bobfile.BTasks[global.DefaultBuildTask] = bobtask.Task{
-		InputDirty:   "./main1.go" + "\n" + "go.mod",
+		InputDirty:   "./main1.go" + "\n" + "./go.mod",
 		CmdDirty:     "go build -o run-build",
 		TargetDirty:  "run-build",
 		RebuildDirty: string(bobtask.RebuildOnChange),
@@ -286,7 +286,7 @@ func createPlaygroundBobfile(dir string, overwrite bool, projectName string) (er
 	}
 
 	bobfile.BTasks[BuildAlwaysTargetName] = bobtask.Task{
-		InputDirty:   "./main1.go" + "\n" + "go.mod",
+		InputDirty:   "./main1.go" + "\n" + "./go.mod",
 		CmdDirty:     "go build -o run-always",
 		TargetDirty:  "run-always",
 		RebuildDirty: string(bobtask.RebuildAlways),
@@ -357,7 +357,7 @@ func createPlaygroundBobfile(dir string, overwrite bool, projectName string) (er
 	}
 
 	bobfile.BTasks["ignoredInputs"] = bobtask.Task{
-		InputDirty: "fileToWatch" + "\n" + "!fileToIgnore",
+		InputDirty: "./fileToWatch" + "\n" + "!./fileToIgnore",
 		CmdDirty:   "echo \"Hello from ignored inputs task\"",
 	}
 
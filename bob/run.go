@@ -29,8 +29,6 @@ import (
 // A control is returned to interact with the run cmd.
 //
 // Canceling the cmd from the outside must be done through the context.
-//
-// FIXME: Forbid circular dependecys.
 func (b *B) Run(ctx context.Context, runTaskName string) (_ ctl.Commander, err error) {
 	defer errz.Recover(&err)
 
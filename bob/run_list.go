@@ -6,7 +6,9 @@ import (
 	"github.com/benchkram/errz"
 )
 
-func (b *B) GetRunTasks() (tasks []string, err error) {
+// GetRunTasks returns the names of all run tasks,
+// limited to the tasks matching the filters if given.
+func (b *B) GetRunTasks(filters ...TagFilter) (tasks []string, err error) {
 	defer errz.Recover(&err)
 
 	aggregate, err := b.AggregateSparse()
@@ -14,6 +16,9 @@ func (b *B) GetRunTasks() (tasks []string, err error) {
 
 	keys := make([]string, 0, len(aggregate.RTasks))
 	for _, task := range aggregate.RTasks {
+		if !matchAll(filters, task.Tags) {
+			continue
+		}
 		keys = append(keys, task.Name())
 	}
 	sort.Strings(keys)
@@ -0,0 +1,48 @@
+package bob
+
+// TagFilter selects tasks by their tags.
+type TagFilter struct {
+	// Tags selects the tasks carrying at least one of the tags.
+	// All tasks are selected when empty.
+	Tags []string
+
+	// ExcludeTags deselects the tasks carrying one of the tags.
+	ExcludeTags []string
+}
+
+// IsEmpty reports if the filter selects all tasks.
+func (f TagFilter) IsEmpty() bool {
+	return len(f.Tags) == 0 && len(f.ExcludeTags) == 0
+}
+
+// Match reports if a task with the given tags is selected.
+func (f TagFilter) Match(tags []string) bool {
+	if containsAny(tags, f.ExcludeTags) {
+		return false
+	}
+	if len(f.Tags) == 0 {
+		return true
+	}
+	return containsAny(tags, f.Tags)
+}
+
+// matchAll reports if a task with the given tags is selected by all filters.
+func matchAll(filters []TagFilter, tags []string) bool {
+	for _, f := range filters {
+		if !f.Match(tags) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsAny(tags []string, wanted []string) bool {
+	for _, tag := range tags {
+		for _, w := range wanted {
+			if tag == w {
+				return true
+			}
+		}
+	}
+	return false
+}
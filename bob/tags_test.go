@@ -0,0 +1,103 @@
+package bob
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTagFilter(t *testing.T) {
+	assert.True(t, TagFilter{}.IsEmpty())
+	assert.True(t, TagFilter{}.Match(nil))
+
+	f := TagFilter{Tags: []string{"test", "lint"}, ExcludeTags: []string{"slow"}}
+	assert.False(t, f.IsEmpty())
+	assert.True(t, f.Match([]string{"lint"}))
+	assert.True(t, f.Match([]string{"go", "test"}))
+	assert.False(t, f.Match([]string{"test", "slow"}))
+	assert.False(t, f.Match([]string{"go"}))
+	assert.False(t, f.Match(nil))
+
+	f = TagFilter{ExcludeTags: []string{"slow"}}
+	assert.True(t, f.Match(nil))
+	assert.False(t, f.Match([]string{"slow"}))
+}
+
+func TestGetTasksByTag(t *testing.T) {
+	b := bobWithBobfiles(t, map[string]string{
+		"bob.yaml": `
+import: [second]
+build:
+  build:
+    cmd: echo build
+  test:
+    cmd: echo test
+    tags: [test]
+  lint:
+    cmd: echo lint
+    tags: [lint]
+run:
+  server:
+    type: binary
+    path: ./server
+    tags: [dev]
+  database:
+    type: binary
+    path: ./database
+`,
+		"second/bob.yaml": `
+build:
+  test:
+    cmd: echo test
+    tags: [test]
+  e2e:
+    cmd: echo e2e
+    tags: [test, slow]
+`,
+	})
+
+	tasks, err := b.GetBuildTasks()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"build", "lint", "second/e2e", "second/test", "test"}, tasks)
+
+	tasks, err = b.GetBuildTasks(TagFilter{Tags: []string{"test"}})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"second/e2e", "second/test", "test"}, tasks)
+
+	tasks, err = b.GetBuildTasks(TagFilter{Tags: []string{"test", "lint"}, ExcludeTags: []string{"slow"}})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"lint", "second/test", "test"}, tasks)
+
+	tasks, err = b.GetBuildTasks(TagFilter{ExcludeTags: []string{"test"}})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"build", "lint"}, tasks)
+
+	runs, err := b.GetRunTasks(TagFilter{Tags: []string{"dev"}})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"server"}, runs)
+}
+
+func TestTagsNotInInputHash(t *testing.T) {
+	bobfile := `
+build:
+  build:
+    cmd: echo build
+    tags: [test]
+`
+	b := bobWithBobfiles(t, map[string]string{"bob.yaml": bobfile})
+	hash := func() string {
+		ag, err := b.Aggregate()
+		assert.Nil(t, err)
+		task := ag.BTasks["build"]
+		h, err := task.HashIn()
+		assert.Nil(t, err)
+		return h.String()
+	}
+	initial := hash()
+
+	bobfile = strings.Replace(bobfile, "tags: [test]", "tags: [test, slow]", 1)
+	assert.Nil(t, os.WriteFile("bob.yaml", []byte(bobfile), 0664))
+	assert.Equal(t, initial, hash())
+}
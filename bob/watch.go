@@ -0,0 +1,216 @@
+package bob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/benchkram/errz"
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/benchkram/bob/bob/global"
+	"github.com/benchkram/bob/bob/playbook"
+	"github.com/benchkram/bob/bobtask"
+	"github.com/benchkram/bob/pkg/boblog"
+	"github.com/benchkram/bob/pkg/filepathutil"
+	"github.com/benchkram/bob/pkg/usererror"
+)
+
+// watchDebounce is the time to wait for further
+// file changes before a rebuild is triggered.
+// Editors tend to write a file multiple times on save.
+const watchDebounce = 300 * time.Millisecond
+
+var errWatcherClosed = fmt.Errorf("watcher closed")
+
+// Watch builds tasks and their dependencies and keeps rebuilding
+// whenever an input of a task in the pipeline changes.
+//
+// The aggregate is only read once. Only the tasks with changed inputs
+// have their input hash recomputed on subsequent builds.
+// Build failures are reported, watching continues till the context is canceled.
+func (b *B) Watch(ctx context.Context, taskNames ...string) (err error) {
+	defer errz.Recover(&err)
+
+	ag, err := b.Aggregate()
+	errz.Fatal(err)
+
+	b.PrintVersionCompatibility(ag)
+
+	taskNames = bobtask.ExpandMatrixNames(taskNames, ag.BTasks.Matrices())
+
+	err = b.nix.BuildNixDependenciesInPipeline(ag, taskNames...)
+	errz.Fatal(err)
+
+	p, err := ag.PlaybookMultiRoot(taskNames, b.playbookOptions(ag)...)
+	errz.Fatal(err)
+
+	watcher, err := fsnotify.NewWatcher()
+	errz.Fatal(err)
+	defer watcher.Close()
+
+	for _, status := range p.Tasks {
+		err = watchDirRecursive(watcher, status.Dir())
+		errz.Fatal(err)
+	}
+
+	for {
+		err = b.play(ctx, taskNames, p)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			reportWatchBuildError(err)
+		}
+
+		boblog.Log.V(1).Info("Watching for changes...")
+		for {
+			changes, err := waitForChanges(ctx, watcher)
+			if ctx.Err() != nil {
+				return nil
+			}
+			errz.Fatal(err)
+
+			invalidated, err := invalidateChangedTasks(p, changes)
+			errz.Fatal(err)
+
+			if len(invalidated) > 0 {
+				boblog.Log.V(1).Info(fmt.Sprintf("Inputs changed for %s", strings.Join(invalidated, ", ")))
+				break
+			}
+		}
+
+		// A playbook can only be played once. Start a new one
+		// which shares the tasks, including their cached input hashes.
+		// Options are recreated to pick up the latest task durations.
+		next := playbook.New(taskNames, b.playbookOptions(ag)...)
+		for name, status := range p.Tasks {
+			next.Tasks[name] = playbook.NewStatus(status.Task)
+		}
+		p = next
+	}
+}
+
+func reportWatchBuildError(err error) {
+	if errors.As(err, &usererror.Err) {
+		boblog.Log.UserError(err)
+		return
+	}
+	boblog.Log.Error(err, "Build failed")
+}
+
+// waitForChanges blocks till a burst of file changes is over.
+// Returns the changed paths and the accumulated operations on them.
+func waitForChanges(ctx context.Context, watcher *fsnotify.Watcher) (_ map[string]fsnotify.Op, err error) {
+	changes := make(map[string]fsnotify.Op)
+
+	// debounce is nil (blocking) till the first change is received.
+	var debounce <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil, errWatcherClosed
+			}
+
+			path, err := filepath.Abs(event.Name)
+			if err != nil {
+				return nil, err
+			}
+			changes[path] |= event.Op
+
+			// New directories must be watched explicitly.
+			if event.Op&fsnotify.Create != 0 {
+				if info, err := os.Stat(path); err == nil && info.IsDir() {
+					err = watchDirRecursive(watcher, path)
+					if err != nil {
+						return nil, err
+					}
+				}
+			}
+
+			debounce = time.After(watchDebounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil, errWatcherClosed
+			}
+			boblog.Log.V(3).Info(fmt.Sprintf("file watcher error: %s", err))
+		case <-debounce:
+			return changes, nil
+		}
+	}
+}
+
+// invalidateChangedTasks clears the cached input hash of all tasks
+// whose inputs are affected by the given changes.
+// Returns the names of the invalidated tasks.
+func invalidateChangedTasks(p *playbook.Playbook, changes map[string]fsnotify.Op) (invalidated []string, err error) {
+	// structural changes might add or remove inputs.
+	var structural bool
+	for _, op := range changes {
+		if op&(fsnotify.Create|fsnotify.Remove|fsnotify.Rename) != 0 {
+			structural = true
+			break
+		}
+	}
+
+	for name, status := range p.Tasks {
+		task := status.Task
+
+		var changed bool
+		for _, input := range task.Inputs() {
+			if _, ok := changes[input]; ok {
+				changed = true
+				break
+			}
+		}
+
+		if structural {
+			updated, err := task.UpdateInputs()
+			if err != nil {
+				return nil, err
+			}
+			changed = changed || updated
+		}
+
+		if changed {
+			task.ClearHashIn()
+			invalidated = append(invalidated, name)
+		}
+	}
+
+	sort.Strings(invalidated)
+	return invalidated, nil
+}
+
+// watchDirRecursive adds dir and all it's subdirectories to the watcher.
+// Directories ignored by default (e.g. `.git`) and bob's cache dir are skipped.
+func watchDirRecursive(watcher *fsnotify.Watcher, dir string) error {
+	return filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// directory might have been removed in the meantime.
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+
+		if !d.IsDir() {
+			return nil
+		}
+
+		if filepathutil.DefaultIgnores[d.Name()] || d.Name() == global.BobCacheDir {
+			return fs.SkipDir
+		}
+
+		return watcher.Add(p)
+	})
+}
@@ -0,0 +1,76 @@
+package bob
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/benchkram/bob/bob/playbook"
+	"github.com/benchkram/bob/bobtask"
+	"github.com/benchkram/bob/pkg/nix"
+)
+
+func TestInvalidateChangedTasks(t *testing.T) {
+	p := playbook.New([]string{"build"})
+
+	for name, inputs := range map[string][]string{
+		"build":  {"/project/main.go"},
+		"lint":   {"/project/main.go", "/project/.golangci.yml"},
+		"assets": {"/project/assets/logo.png"},
+	} {
+		task := bobtask.Make()
+		task.SetName(name)
+		task.SetInputs(inputs)
+		p.Tasks[name] = playbook.NewStatus(&task)
+	}
+
+	invalidated, err := invalidateChangedTasks(p, map[string]fsnotify.Op{
+		"/project/main.go": fsnotify.Write,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"build", "lint"}, invalidated)
+
+	invalidated, err = invalidateChangedTasks(p, map[string]fsnotify.Op{
+		"/project/README.md": fsnotify.Write,
+	})
+	assert.Nil(t, err)
+	assert.Empty(t, invalidated)
+}
+
+func TestWatchRecordsBuilds(t *testing.T) {
+	if !nix.IsInstalled() {
+		t.Skip("nix is not installed")
+	}
+
+	nixCache, err := nix.NewCacheStore(nix.WithPath(filepath.Join(t.TempDir(), "nix")))
+	assert.Nil(t, err)
+
+	b := bobWithBobfiles(t, map[string]string{"bob.yaml": `
+build:
+  build:
+    cmd: echo build
+nixpkgs: https://github.com/NixOS/nixpkgs/archive/eeefd01d4f630fcbab6588fe3e7fffe0690fbb20.tar.gz
+`}, WithNixBuilder(NewNixBuilder(WithCache(nixCache))))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error)
+	go func() { done <- b.Watch(ctx, "build") }()
+
+	// the initial build is recorded like any other build
+	assert.Eventually(t, func() bool {
+		runs, err := b.History()
+		return err == nil && len(runs) == 1
+	}, 10*time.Second, 10*time.Millisecond)
+	cancel()
+	assert.Nil(t, <-done)
+
+	durations, err := b.durationStore.Durations(b.dir)
+	assert.Nil(t, err)
+	assert.Contains(t, durations, "build")
+}
@@ -0,0 +1,81 @@
+package bob
+
+import (
+	"github.com/benchkram/errz"
+
+	"github.com/benchkram/bob/bobtask/buildinfo"
+)
+
+// RebuildExplanation compares the current inputs of a
+// task with the inputs of its last successful build.
+type RebuildExplanation struct {
+	TaskName string
+
+	// UpToDate is true when a buildinfo for the
+	// current input hash of the task exists.
+	UpToDate bool
+
+	// Previous is the buildinfo of the last successful build,
+	// nil if the task has never been built or the buildinfo
+	// was written by a version of bob not recording its inputs.
+	Previous *buildinfo.I
+
+	// Diff between the current inputs and the inputs of the previous build.
+	// Nil if there is no previous build.
+	Diff *buildinfo.InputManifestDiff
+}
+
+// Why explains why a task would be rebuilt by diffing its current
+// inputs against the inputs of the last successful build.
+func (b *B) Why(taskName string) (_ *RebuildExplanation, err error) {
+	defer errz.Recover(&err)
+
+	ag, err := b.Aggregate()
+	errz.Fatal(err)
+
+	// Nix dependencies are considered in the input hash of a task.
+	err = b.nix.BuildNixDependenciesInPipeline(ag, taskName)
+	errz.Fatal(err)
+
+	p, err := ag.Playbook(taskName, b.playbookOptions(ag)...)
+	errz.Fatal(err)
+	task := p.Tasks[taskName].Task
+
+	hashIn, err := task.HashIn()
+	errz.Fatal(err)
+	inputs, err := task.InputManifest()
+	errz.Fatal(err)
+
+	buildinfos, err := b.buildInfoStore.GetBuildInfos()
+	errz.Fatal(err)
+
+	explanation := &RebuildExplanation{TaskName: taskName}
+	explanation.Previous = lastBuildInfo(buildinfos, taskName, task.Project())
+	for _, bi := range buildinfos {
+		if bi.Meta.InputHash == hashIn.String() {
+			explanation.UpToDate = true
+			break
+		}
+	}
+
+	if explanation.Previous != nil {
+		explanation.Diff = inputs.Diff(&explanation.Previous.Meta.Inputs)
+	}
+
+	return explanation, nil
+}
+
+// lastBuildInfo returns the most recent buildinfo of a task
+// which recorded its inputs, nil if none exists.
+func lastBuildInfo(buildinfos []*buildinfo.I, taskName, project string) *buildinfo.I {
+	var last *buildinfo.I
+	for _, bi := range buildinfos {
+		if bi.Meta.Task != taskName || bi.Meta.Project != project || bi.Meta.Time == 0 {
+			continue
+		}
+		if last == nil || bi.Meta.Time > last.Meta.Time {
+			last = bi
+		}
+	}
+	return last
+}
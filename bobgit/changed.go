@@ -0,0 +1,182 @@
+package bobgit
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/logrusorgru/aurora"
+
+	"github.com/benchkram/errz"
+)
+
+var ErrRevisionNotFound = fmt.Errorf("revision not found")
+
+// Changed returns the files changed between the revision since and the
+// worktree of all repositories inside of root, including the repository
+// containing root. Changes are the files changed in commits since the
+// merge-base of the revision and HEAD, staged and unstaged changes and
+// untracked files. Deleted and renamed files are included with their
+// previous path.
+//
+// Repositories the revision can't be resolved in are skipped with a
+// warning, ErrRevisionNotFound is returned when it can't be resolved
+// in any repository.
+//
+// Returns the absolute paths of the changed files inside of root.
+func Changed(root string, since string) (_ []string, err error) {
+	defer errz.Recover(&err)
+
+	root, err = filepath.Abs(root)
+	errz.Fatal(err)
+
+	repoNames, err := findRepos(root)
+	errz.Fatal(err)
+
+	var repoDirs []string
+	for _, name := range repoNames {
+		repoDirs = append(repoDirs, filepath.Join(root, name))
+	}
+
+	// root might be located in a subdirectory of a repository.
+	isGit, err := isGitRepo(root)
+	errz.Fatal(err)
+	if !isGit {
+		r, err := git.PlainOpenWithOptions(root, &git.PlainOpenOptions{DetectDotGit: true})
+		if err != nil && err != git.ErrRepositoryNotExists {
+			errz.Fatal(err)
+		}
+		if err == nil {
+			wt, err := r.Worktree()
+			errz.Fatal(err)
+			repoDirs = append(repoDirs, wt.Filesystem.Root())
+		}
+	}
+
+	if len(repoDirs) == 0 {
+		return nil, ErrCouldNotFindGitDir
+	}
+
+	changed := make(map[string]bool)
+	var notFound error
+	var resolved int
+	for _, repoDir := range repoDirs {
+		paths, err := changedInRepo(repoDir, since)
+		if errors.Is(err, ErrRevisionNotFound) {
+			// stderr keeps the output of the changes parseable.
+			fmt.Fprintln(os.Stderr, aurora.Yellow(fmt.Sprintf("Skipping repository %s, %s", repoDir, err)))
+			notFound = err
+			continue
+		}
+		errz.Fatal(err)
+		resolved++
+
+		for _, path := range paths {
+			path = filepath.Join(repoDir, filepath.FromSlash(path))
+
+			if path != root && !strings.HasPrefix(path, root+string(filepath.Separator)) {
+				continue
+			}
+			// files of a nested repository are reported by the nested repository.
+			if inNestedRepo(path, repoDir, repoDirs) {
+				continue
+			}
+			changed[path] = true
+		}
+	}
+
+	if resolved == 0 {
+		return nil, notFound
+	}
+
+	files := make([]string, 0, len(changed))
+	for path := range changed {
+		files = append(files, path)
+	}
+	sort.Strings(files)
+
+	return files, nil
+}
+
+// changedInRepo returns the paths relative to the repository
+// which changed between the revision since and the worktree.
+func changedInRepo(repoDir string, since string) (_ []string, err error) {
+	defer errz.Recover(&err)
+
+	r, err := git.PlainOpen(repoDir)
+	errz.Fatal(err)
+
+	hash, err := r.ResolveRevision(plumbing.Revision(since))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q in repository %q", ErrRevisionNotFound, since, repoDir)
+	}
+	sinceCommit, err := r.CommitObject(*hash)
+	errz.Fatal(err)
+
+	head, err := r.Head()
+	errz.Fatal(err)
+	headCommit, err := r.CommitObject(head.Hash())
+	errz.Fatal(err)
+	headTree, err := headCommit.Tree()
+	errz.Fatal(err)
+
+	// Commits only on the side of since, e.g. on the base
+	// branch of a pull request, aren't changes of HEAD.
+	bases, err := sinceCommit.MergeBase(headCommit)
+	errz.Fatal(err)
+	if len(bases) > 0 {
+		sinceCommit = bases[0]
+	}
+	sinceTree, err := sinceCommit.Tree()
+	errz.Fatal(err)
+
+	var paths []string
+
+	// committed changes
+	changes, err := object.DiffTree(sinceTree, headTree)
+	errz.Fatal(err)
+	for _, change := range changes {
+		if change.From.Name != "" {
+			paths = append(paths, change.From.Name)
+		}
+		if change.To.Name != "" && change.To.Name != change.From.Name {
+			paths = append(paths, change.To.Name)
+		}
+	}
+
+	// uncommitted changes
+	wt, err := r.Worktree()
+	errz.Fatal(err)
+	status, err := wt.Status()
+	errz.Fatal(err)
+	for path, s := range status {
+		if s.Staging == git.Unmodified && s.Worktree == git.Unmodified {
+			continue
+		}
+		paths = append(paths, path)
+		if s.Extra != "" {
+			paths = append(paths, s.Extra)
+		}
+	}
+
+	return paths, nil
+}
+
+// inNestedRepo reports if path belongs to one of repoDirs nested inside of repoDir.
+func inNestedRepo(path string, repoDir string, repoDirs []string) bool {
+	for _, dir := range repoDirs {
+		if dir == repoDir || !strings.HasPrefix(dir, repoDir+string(filepath.Separator)) {
+			continue
+		}
+		if path == dir || strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+package bobgit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benchkram/bob/pkg/cmdutil"
+)
+
+func TestChanged(t *testing.T) {
+	setGitIdentity(t)
+
+	dir := t.TempDir()
+	dir, err := filepath.EvalSymlinks(dir)
+	require.Nil(t, err)
+
+	write := func(path, content string) {
+		path = filepath.Join(dir, path)
+		require.Nil(t, os.MkdirAll(filepath.Dir(path), 0775))
+		require.Nil(t, os.WriteFile(path, []byte(content), 0664))
+	}
+	commit := func(repo string) {
+		require.Nil(t, cmdutil.RunGit(filepath.Join(dir, repo), "add", "-A"))
+		require.Nil(t, cmdutil.RunGit(filepath.Join(dir, repo), "commit", "-m", "commit"))
+	}
+
+	defaultBranch, err := initGit(dir)
+	require.Nil(t, err)
+	write(".gitignore", "repo/\nignored\n")
+	write("modified", "modified")
+	write("deleted", "deleted")
+	write("renamed", "renamed")
+	write("unchanged", "unchanged")
+	commit(".")
+
+	repo := filepath.Join(dir, "repo")
+	require.Nil(t, os.MkdirAll(repo, 0775))
+	_, err = initGit(repo)
+	require.Nil(t, err)
+	write("repo/modified", "modified")
+	write("repo/unchanged", "unchanged")
+	commit("repo")
+
+	require.Nil(t, cmdutil.RunGit(dir, "tag", "base"))
+	require.Nil(t, cmdutil.RunGit(repo, "tag", "base"))
+
+	// commits of another branch only
+	require.Nil(t, cmdutil.RunGit(dir, "checkout", "-b", "side"))
+	write("side", "side")
+	commit(".")
+	require.Nil(t, cmdutil.RunGit(dir, "checkout", defaultBranch))
+
+	// committed
+	write("modified", "modified again")
+	require.Nil(t, cmdutil.RunGit(dir, "mv", "renamed", "renamed-new"))
+	commit(".")
+
+	// staged, unstaged and untracked
+	assert.Nil(t, os.Remove(filepath.Join(dir, "deleted")))
+	write("untracked", "untracked")
+	write("ignored", "ignored")
+	write("repo/modified", "modified again")
+
+	changed, err := Changed(dir, "base")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "deleted"),
+		filepath.Join(dir, "modified"),
+		filepath.Join(dir, "renamed"),
+		filepath.Join(dir, "renamed-new"),
+		filepath.Join(dir, "repo", "modified"),
+		filepath.Join(dir, "untracked"),
+	}, changed)
+
+	// changes since the merge-base, the nested
+	// repository without the revision is skipped
+	changed, err = Changed(dir, "side")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "deleted"),
+		filepath.Join(dir, "modified"),
+		filepath.Join(dir, "renamed"),
+		filepath.Join(dir, "renamed-new"),
+		filepath.Join(dir, "untracked"),
+	}, changed)
+
+	// limited to a subdirectory
+	changed, err = Changed(repo, "base")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{filepath.Join(repo, "modified")}, changed)
+
+	_, err = Changed(dir, "does-not-exist")
+	assert.ErrorIs(t, err, ErrRevisionNotFound)
+	_, err = Changed(repo, "side")
+	assert.ErrorIs(t, err, ErrRevisionNotFound)
+}
+
+// setGitIdentity allows to commit without a configured git identity.
+func setGitIdentity(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "bob")
+	t.Setenv("GIT_AUTHOR_EMAIL", "bob@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "bob")
+	t.Setenv("GIT_COMMITTER_EMAIL", "bob@example.com")
+}
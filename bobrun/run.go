@@ -26,6 +26,16 @@ type Run struct {
 	// DependsOn run or build tasks
 	DependsOn []string `yaml:"dependsOn"`
 
+	// Tags are used to select tasks on the command line, e.g. `bob run ls --tag db`.
+	Tags []string `yaml:"tags"`
+
+	// EnvDirty are environment variables of the run,
+	// taking precedence over the variables of the bobfile.
+	EnvDirty map[string]string `yaml:"env"`
+
+	// PassEnv lists environment variables passed from the host to the run.
+	PassEnv []string `yaml:"passEnv"`
+
 	// InitDirty runs run after this task has started and `initOnce`conpleted.
 	InitDirty string `yaml:"init"`
 	// init see InitDirty
@@ -3,6 +3,7 @@ package bobtask
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
@@ -17,11 +18,14 @@ import (
 
 	"github.com/benchkram/bob/bobtask/hash"
 	"github.com/benchkram/bob/pkg/boblog"
+	"github.com/benchkram/bob/pkg/store"
+	"github.com/benchkram/bob/pkg/tasklog"
 )
 
 const __targetsFilesystem = "targets/filesystem"
 const __targetsDocker = "targets/docker"
 const __metadata = "__metadata"
+const __log = "__log"
 
 var ErrInvalidTarHeaderType = fmt.Errorf("invalid tar header type")
 
@@ -66,12 +70,23 @@ func (t *Task) ArtifactCreate(artifactName hash.In) (err error) {
 
 	artifact, err := t.local.NewArtifact(context.TODO(), artifactName.String(), 0)
 	errz.Fatal(err)
-	defer artifact.Close()
 
 	archiveWriter := newArchiveWriter()
+
+	// An incomplete artifact is removed,
+	// it must not be taken for a valid one.
+	var complete bool
+	defer func() {
+		if complete {
+			return
+		}
+		_ = archiveWriter.Close()
+		_ = store.Abort(artifact)
+		_ = t.local.ArtifactRemove(context.TODO(), artifactName.String())
+	}()
+
 	err = archiveWriter.Create(artifact)
 	errz.Fatal(err)
-	defer archiveWriter.Close()
 
 	boblog.Log.V(3).Info(fmt.Sprintf("[task:%s] file in buildinfo %d", t.name, len(buildInfo.Filesystem.Files)))
 
@@ -171,6 +186,31 @@ func (t *Task) ArtifactCreate(artifactName hash.In) (err error) {
 	})
 	errz.Fatal(err)
 
+	// store the log of the run to be able to replay
+	// it when the artifact is pulled by someone else.
+	if t.logStore != nil {
+		log, err := t.logStore.Get(t.name, artifactName.String())
+		if err != nil && !errors.Is(err, tasklog.ErrLogDoesNotExist) {
+			errz.Fatal(err)
+		}
+		if err == nil {
+			err = archiveWriter.Write(archiver.File{
+				FileInfo: fileInfo{
+					name: __log,
+					data: log,
+				},
+				ReadCloser: io.NopCloser(bytes.NewBuffer(log)),
+			})
+			errz.Fatal(err)
+		}
+	}
+
+	err = archiveWriter.Close()
+	errz.Fatal(err)
+	err = artifact.Close()
+	errz.Fatal(err)
+	complete = true
+
 	return nil
 }
 
@@ -116,6 +116,14 @@ func (t *Task) ArtifactExtract(artifactName hash.In) (success bool, err error) {
 			defer func() { _ = os.Remove(dst) }()
 		}
 
+		// log of the run which created the artifact
+		if header.Name == __log && t.logStore != nil {
+			log, err := t.logStore.Create(t.name, artifactName.String())
+			errz.Fatal(err)
+			_, err = io.Copy(log, archiveFile)
+			_ = log.Close()
+			errz.Fatal(err)
+		}
 	}
 
 	return true, nil
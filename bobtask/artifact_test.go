@@ -1,6 +1,9 @@
 package bobtask
 
 import (
+	"context"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -8,6 +11,7 @@ import (
 
 	"github.com/benchkram/bob/pkg/buildinfostore"
 	"github.com/benchkram/bob/pkg/file"
+	"github.com/benchkram/bob/pkg/store"
 	"github.com/benchkram/bob/pkg/store/filestore"
 	"github.com/benchkram/errz"
 	"github.com/stretchr/testify/assert"
@@ -65,3 +69,44 @@ func TestArtifactCreateAndExtract(t *testing.T) {
 	_, err = tsk.ArtifactInspect("aaa")
 	assert.Nil(t, err)
 }
+
+// failingStore fails to write artifacts.
+type failingStore struct {
+	store.Store
+}
+
+func (s failingStore) NewArtifact(ctx context.Context, artifactID string, size int64) (io.WriteCloser, error) {
+	w, err := s.Store.NewArtifact(ctx, artifactID, size)
+	return failingWriter{w}, err
+}
+
+type failingWriter struct {
+	io.WriteCloser
+}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, fmt.Errorf("disk full")
+}
+
+func TestArtifactCreateIncomplete(t *testing.T) {
+	testdir := t.TempDir()
+	artifactStore := filestore.New(t.TempDir())
+
+	assert.Nil(t, os.MkdirAll(filepath.Join(testdir, ".bbuild"), 0774))
+	assert.Nil(t, os.WriteFile(filepath.Join(testdir, ".bbuild/fileone"), []byte("fileone"), 0774))
+
+	tsk := Make()
+	tsk.dir = testdir
+	tsk.local = failingStore{artifactStore}
+	tsk.buildInfoStore = buildinfostore.NewProtoStore(t.TempDir())
+	tsk.name = "mytaskname"
+	tsk.TargetDirty = ".bbuild/"
+	assert.Nil(t, tsk.parseTargets())
+
+	assert.NotNil(t, tsk.ArtifactCreate("aaa"))
+	assert.False(t, artifactStore.ArtifactExists(context.Background(), "aaa"))
+
+	items, err := artifactStore.List(context.Background())
+	assert.Nil(t, err)
+	assert.Empty(t, items)
+}
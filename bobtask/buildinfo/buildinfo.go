@@ -14,6 +14,9 @@ type I struct {
 
 func New() *I {
 	return &I{
+		Meta: Meta{
+			Inputs: MakeInputManifest(),
+		},
 		Target: MakeTargets(),
 	}
 }
@@ -66,6 +69,15 @@ type Meta struct {
 
 	// InputHash used for target creation
 	InputHash string `yaml:"input_hash"`
+
+	// Project the task belongs to
+	Project string `yaml:"project"`
+
+	// Inputs the input hash was computed from
+	Inputs InputManifest `yaml:"inputs"`
+
+	// Time of creation as unix timestamp
+	Time int64 `yaml:"time"`
 }
 
 func (i *I) ToProto(inputHash string) *protos.BuildInfo {
@@ -87,6 +99,13 @@ func (i *I) ToProto(inputHash string) *protos.BuildInfo {
 		Meta: &protos.Meta{
 			Task:      i.Meta.Task,
 			InputHash: inputHash,
+			Project:   i.Meta.Project,
+			Inputs: &protos.InputManifest{
+				Files:       i.Meta.Inputs.Files,
+				Env:         i.Meta.Inputs.Env,
+				Description: i.Meta.Inputs.Description,
+			},
+			Time: i.Meta.Time,
 		},
 		Target: &protos.Targets{
 			Filesystem: filesystem,
@@ -105,6 +124,18 @@ func FromProto(p *protos.BuildInfo) *I {
 	if p.Meta != nil {
 		bi.Meta.Task = p.Meta.Task
 		bi.Meta.InputHash = p.Meta.InputHash
+		bi.Meta.Project = p.Meta.Project
+		bi.Meta.Time = p.Meta.Time
+
+		if p.Meta.Inputs != nil {
+			for k, v := range p.Meta.Inputs.Files {
+				bi.Meta.Inputs.Files[k] = v
+			}
+			for k, v := range p.Meta.Inputs.Env {
+				bi.Meta.Inputs.Env[k] = v
+			}
+			bi.Meta.Inputs.Description = p.Meta.Inputs.Description
+		}
 	}
 
 	if p.Target != nil {
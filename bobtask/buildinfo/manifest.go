@@ -0,0 +1,89 @@
+package buildinfo
+
+import "sort"
+
+// InputManifest holds digests of everything the
+// input hash of a task is computed from.
+type InputManifest struct {
+	// Files maps each input file to the hash of its content
+	Files map[string]string `yaml:"files"`
+
+	// Env maps each environment variable to the hash of its value.
+	// Values are not stored as they might contain secrets.
+	Env map[string]string `yaml:"env"`
+
+	// Description is the hash of the task description
+	Description string `yaml:"description"`
+}
+
+func NewInputManifest() *InputManifest {
+	return &InputManifest{
+		Files: make(map[string]string),
+		Env:   make(map[string]string),
+	}
+}
+
+func MakeInputManifest() InputManifest {
+	return *NewInputManifest()
+}
+
+// InputManifestDiff lists the differences between two input manifests.
+type InputManifestDiff struct {
+	AddedFiles    []string
+	RemovedFiles  []string
+	ModifiedFiles []string
+
+	// ChangedEnv contains names of environment variables
+	// which have been added, removed or modified.
+	ChangedEnv []string
+
+	DescriptionChanged bool
+}
+
+// Empty returns true when no differences were found.
+func (d *InputManifestDiff) Empty() bool {
+	return len(d.AddedFiles) == 0 &&
+		len(d.RemovedFiles) == 0 &&
+		len(d.ModifiedFiles) == 0 &&
+		len(d.ChangedEnv) == 0 &&
+		!d.DescriptionChanged
+}
+
+// Diff compares the manifest with a previous one.
+func (m *InputManifest) Diff(previous *InputManifest) *InputManifestDiff {
+	diff := &InputManifestDiff{}
+
+	for f, hash := range m.Files {
+		prevHash, ok := previous.Files[f]
+		if !ok {
+			diff.AddedFiles = append(diff.AddedFiles, f)
+		} else if prevHash != hash {
+			diff.ModifiedFiles = append(diff.ModifiedFiles, f)
+		}
+	}
+	for f := range previous.Files {
+		if _, ok := m.Files[f]; !ok {
+			diff.RemovedFiles = append(diff.RemovedFiles, f)
+		}
+	}
+
+	for k, hash := range m.Env {
+		if prevHash, ok := previous.Env[k]; !ok || prevHash != hash {
+			diff.ChangedEnv = append(diff.ChangedEnv, k)
+		}
+	}
+	for k := range previous.Env {
+		if _, ok := m.Env[k]; !ok {
+			diff.ChangedEnv = append(diff.ChangedEnv, k)
+		}
+	}
+
+	diff.DescriptionChanged = m.Description != previous.Description
+
+	sort.Strings(diff.AddedFiles)
+	sort.Strings(diff.RemovedFiles)
+	sort.Strings(diff.ModifiedFiles)
+	sort.Strings(diff.ChangedEnv)
+
+	return diff
+}
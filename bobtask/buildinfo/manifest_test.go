@@ -0,0 +1,48 @@
+package buildinfo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInputManifestDiff(t *testing.T) {
+	previous := &InputManifest{
+		Files: map[string]string{
+			"/project/main.go":    "a",
+			"/project/go.mod":     "b",
+			"/project/removed.go": "c",
+		},
+		Env: map[string]string{
+			"GOPATH":  "d",
+			"REMOVED": "e",
+		},
+		Description: "f",
+	}
+
+	current := &InputManifest{
+		Files: map[string]string{
+			"/project/main.go":  "changed",
+			"/project/go.mod":   "b",
+			"/project/added.go": "g",
+		},
+		Env: map[string]string{
+			"GOPATH": "changed",
+			"ADDED":  "h",
+		},
+		Description: "f",
+	}
+
+	diff := current.Diff(previous)
+	assert.Equal(t, []string{"/project/added.go"}, diff.AddedFiles)
+	assert.Equal(t, []string{"/project/removed.go"}, diff.RemovedFiles)
+	assert.Equal(t, []string{"/project/main.go"}, diff.ModifiedFiles)
+	assert.Equal(t, []string{"ADDED", "GOPATH", "REMOVED"}, diff.ChangedEnv)
+	assert.False(t, diff.DescriptionChanged)
+	assert.False(t, diff.Empty())
+
+	assert.True(t, current.Diff(current).Empty())
+
+	current.Description = "changed"
+	assert.True(t, current.Diff(previous).DescriptionChanged)
+}
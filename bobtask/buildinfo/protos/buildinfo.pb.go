@@ -80,8 +80,11 @@ type Meta struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Task      string `protobuf:"bytes,1,opt,name=Task,proto3" json:"Task,omitempty"`
-	InputHash string `protobuf:"bytes,2,opt,name=InputHash,proto3" json:"InputHash,omitempty"`
+	Task      string         `protobuf:"bytes,1,opt,name=Task,proto3" json:"Task,omitempty"`
+	InputHash string         `protobuf:"bytes,2,opt,name=InputHash,proto3" json:"InputHash,omitempty"`
+	Project   string         `protobuf:"bytes,3,opt,name=Project,proto3" json:"Project,omitempty"`
+	Inputs    *InputManifest `protobuf:"bytes,4,opt,name=Inputs,proto3" json:"Inputs,omitempty"`
+	Time      int64          `protobuf:"varint,5,opt,name=Time,proto3" json:"Time,omitempty"`
 }
 
 func (x *Meta) Reset() {
@@ -130,6 +133,90 @@ func (x *Meta) GetInputHash() string {
 	return ""
 }
 
+func (x *Meta) GetProject() string {
+	if x != nil {
+		return x.Project
+	}
+	return ""
+}
+
+func (x *Meta) GetInputs() *InputManifest {
+	if x != nil {
+		return x.Inputs
+	}
+	return nil
+}
+
+func (x *Meta) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+type InputManifest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Files       map[string]string `protobuf:"bytes,1,rep,name=Files,proto3" json:"Files,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Env         map[string]string `protobuf:"bytes,2,rep,name=Env,proto3" json:"Env,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Description string            `protobuf:"bytes,3,opt,name=Description,proto3" json:"Description,omitempty"`
+}
+
+func (x *InputManifest) Reset() {
+	*x = InputManifest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_buildinfo_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *InputManifest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InputManifest) ProtoMessage() {}
+
+func (x *InputManifest) ProtoReflect() protoreflect.Message {
+	mi := &file_buildinfo_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InputManifest.ProtoReflect.Descriptor instead.
+func (*InputManifest) Descriptor() ([]byte, []int) {
+	return file_buildinfo_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *InputManifest) GetFiles() map[string]string {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+func (x *InputManifest) GetEnv() map[string]string {
+	if x != nil {
+		return x.Env
+	}
+	return nil
+}
+
+func (x *InputManifest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
 type Targets struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -142,7 +229,7 @@ type Targets struct {
 func (x *Targets) Reset() {
 	*x = Targets{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_buildinfo_proto_msgTypes[2]
+		mi := &file_buildinfo_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -155,7 +242,7 @@ func (x *Targets) String() string {
 func (*Targets) ProtoMessage() {}
 
 func (x *Targets) ProtoReflect() protoreflect.Message {
-	mi := &file_buildinfo_proto_msgTypes[2]
+	mi := &file_buildinfo_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -168,7 +255,7 @@ func (x *Targets) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Targets.ProtoReflect.Descriptor instead.
 func (*Targets) Descriptor() ([]byte, []int) {
-	return file_buildinfo_proto_rawDescGZIP(), []int{2}
+	return file_buildinfo_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *Targets) GetFilesystem() *BuildInfoFiles {
@@ -197,7 +284,7 @@ type BuildInfoFiles struct {
 func (x *BuildInfoFiles) Reset() {
 	*x = BuildInfoFiles{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_buildinfo_proto_msgTypes[3]
+		mi := &file_buildinfo_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -210,7 +297,7 @@ func (x *BuildInfoFiles) String() string {
 func (*BuildInfoFiles) ProtoMessage() {}
 
 func (x *BuildInfoFiles) ProtoReflect() protoreflect.Message {
-	mi := &file_buildinfo_proto_msgTypes[3]
+	mi := &file_buildinfo_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -223,7 +310,7 @@ func (x *BuildInfoFiles) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BuildInfoFiles.ProtoReflect.Descriptor instead.
 func (*BuildInfoFiles) Descriptor() ([]byte, []int) {
-	return file_buildinfo_proto_rawDescGZIP(), []int{3}
+	return file_buildinfo_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *BuildInfoFiles) GetHash() string {
@@ -251,7 +338,7 @@ type BuildInfoFile struct {
 func (x *BuildInfoFile) Reset() {
 	*x = BuildInfoFile{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_buildinfo_proto_msgTypes[4]
+		mi := &file_buildinfo_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -264,7 +351,7 @@ func (x *BuildInfoFile) String() string {
 func (*BuildInfoFile) ProtoMessage() {}
 
 func (x *BuildInfoFile) ProtoReflect() protoreflect.Message {
-	mi := &file_buildinfo_proto_msgTypes[4]
+	mi := &file_buildinfo_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -277,7 +364,7 @@ func (x *BuildInfoFile) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BuildInfoFile.ProtoReflect.Descriptor instead.
 func (*BuildInfoFile) Descriptor() ([]byte, []int) {
-	return file_buildinfo_proto_rawDescGZIP(), []int{4}
+	return file_buildinfo_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *BuildInfoFile) GetSize() int64 {
@@ -298,7 +385,7 @@ type BuildInfoDocker struct {
 func (x *BuildInfoDocker) Reset() {
 	*x = BuildInfoDocker{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_buildinfo_proto_msgTypes[5]
+		mi := &file_buildinfo_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -311,7 +398,7 @@ func (x *BuildInfoDocker) String() string {
 func (*BuildInfoDocker) ProtoMessage() {}
 
 func (x *BuildInfoDocker) ProtoReflect() protoreflect.Message {
-	mi := &file_buildinfo_proto_msgTypes[5]
+	mi := &file_buildinfo_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -324,7 +411,7 @@ func (x *BuildInfoDocker) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BuildInfoDocker.ProtoReflect.Descriptor instead.
 func (*BuildInfoDocker) Descriptor() ([]byte, []int) {
-	return file_buildinfo_proto_rawDescGZIP(), []int{5}
+	return file_buildinfo_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *BuildInfoDocker) GetHash() string {
@@ -343,41 +430,63 @@ var file_buildinfo_proto_rawDesc = []byte{
 	0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x62, 0x6f, 0x62, 0x2e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74,
 	0x73, 0x52, 0x06, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12, 0x1d, 0x0a, 0x04, 0x4d, 0x65, 0x74,
 	0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x62, 0x6f, 0x62, 0x2e, 0x4d, 0x65,
-	0x74, 0x61, 0x52, 0x04, 0x4d, 0x65, 0x74, 0x61, 0x22, 0x38, 0x0a, 0x04, 0x4d, 0x65, 0x74, 0x61,
-	0x12, 0x12, 0x0a, 0x04, 0x54, 0x61, 0x73, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
-	0x54, 0x61, 0x73, 0x6b, 0x12, 0x1c, 0x0a, 0x09, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x48, 0x61, 0x73,
-	0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x48, 0x61,
-	0x73, 0x68, 0x22, 0xc1, 0x01, 0x0a, 0x07, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x12, 0x33,
-	0x0a, 0x0a, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x13, 0x2e, 0x62, 0x6f, 0x62, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x6e,
-	0x66, 0x6f, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x52, 0x0a, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x79, 0x73,
-	0x74, 0x65, 0x6d, 0x12, 0x30, 0x0a, 0x06, 0x44, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x18, 0x02, 0x20,
-	0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x62, 0x6f, 0x62, 0x2e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74,
-	0x73, 0x2e, 0x44, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x44,
-	0x6f, 0x63, 0x6b, 0x65, 0x72, 0x1a, 0x4f, 0x0a, 0x0b, 0x44, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x45,
-	0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2a, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x62, 0x6f, 0x62, 0x2e, 0x42, 0x75, 0x69, 0x6c,
-	0x64, 0x49, 0x6e, 0x66, 0x6f, 0x44, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x52, 0x05, 0x76, 0x61, 0x6c,
-	0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xb0, 0x01, 0x0a, 0x0e, 0x42, 0x75, 0x69, 0x6c, 0x64,
-	0x49, 0x6e, 0x66, 0x6f, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x48, 0x61, 0x73,
-	0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x48, 0x61, 0x73, 0x68, 0x12, 0x3a, 0x0a,
-	0x07, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x20,
-	0x2e, 0x62, 0x6f, 0x62, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x6e, 0x66, 0x6f, 0x46, 0x69,
-	0x6c, 0x65, 0x73, 0x2e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
-	0x52, 0x07, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x1a, 0x4e, 0x0a, 0x0c, 0x54, 0x61, 0x72,
-	0x67, 0x65, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x28, 0x0a, 0x05, 0x76,
-	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x62, 0x6f, 0x62,
-	0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x6e, 0x66, 0x6f, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x05,
-	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x23, 0x0a, 0x0d, 0x42, 0x75, 0x69,
-	0x6c, 0x64, 0x49, 0x6e, 0x66, 0x6f, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x53, 0x69,
-	0x7a, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x53, 0x69, 0x7a, 0x65, 0x22, 0x25,
-	0x0a, 0x0f, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x6e, 0x66, 0x6f, 0x44, 0x6f, 0x63, 0x6b, 0x65,
-	0x72, 0x12, 0x12, 0x0a, 0x04, 0x48, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x04, 0x48, 0x61, 0x73, 0x68, 0x42, 0x1a, 0x5a, 0x18, 0x62, 0x6f, 0x62, 0x74, 0x61, 0x73, 0x6b,
-	0x2f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x69, 0x6e, 0x66, 0x6f, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x74, 0x61, 0x52, 0x04, 0x4d, 0x65, 0x74, 0x61, 0x22, 0x92, 0x01, 0x0a, 0x04, 0x4d, 0x65, 0x74,
+	0x61, 0x12, 0x12, 0x0a, 0x04, 0x54, 0x61, 0x73, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x54, 0x61, 0x73, 0x6b, 0x12, 0x1c, 0x0a, 0x09, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x48, 0x61,
+	0x73, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x48,
+	0x61, 0x73, 0x68, 0x12, 0x18, 0x0a, 0x07, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x2a, 0x0a,
+	0x06, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e,
+	0x62, 0x6f, 0x62, 0x2e, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x4d, 0x61, 0x6e, 0x69, 0x66, 0x65, 0x73,
+	0x74, 0x52, 0x06, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x54, 0x69, 0x6d,
+	0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x54, 0x69, 0x6d, 0x65, 0x22, 0x87, 0x02,
+	0x0a, 0x0d, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x4d, 0x61, 0x6e, 0x69, 0x66, 0x65, 0x73, 0x74, 0x12,
+	0x33, 0x0a, 0x05, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d,
+	0x2e, 0x62, 0x6f, 0x62, 0x2e, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x4d, 0x61, 0x6e, 0x69, 0x66, 0x65,
+	0x73, 0x74, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x05, 0x46,
+	0x69, 0x6c, 0x65, 0x73, 0x12, 0x2d, 0x0a, 0x03, 0x45, 0x6e, 0x76, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x1b, 0x2e, 0x62, 0x6f, 0x62, 0x2e, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x4d, 0x61, 0x6e,
+	0x69, 0x66, 0x65, 0x73, 0x74, 0x2e, 0x45, 0x6e, 0x76, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x03,
+	0x45, 0x6e, 0x76, 0x12, 0x20, 0x0a, 0x0b, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x1a, 0x38, 0x0a, 0x0a, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a,
+	0x36, 0x0a, 0x08, 0x45, 0x6e, 0x76, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b,
+	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xc1, 0x01, 0x0a, 0x07, 0x54, 0x61, 0x72, 0x67,
+	0x65, 0x74, 0x73, 0x12, 0x33, 0x0a, 0x0a, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x79, 0x73, 0x74, 0x65,
+	0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x62, 0x6f, 0x62, 0x2e, 0x42, 0x75,
+	0x69, 0x6c, 0x64, 0x49, 0x6e, 0x66, 0x6f, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x52, 0x0a, 0x46, 0x69,
+	0x6c, 0x65, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x12, 0x30, 0x0a, 0x06, 0x44, 0x6f, 0x63, 0x6b,
+	0x65, 0x72, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x62, 0x6f, 0x62, 0x2e, 0x54,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x2e, 0x44, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x52, 0x06, 0x44, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x1a, 0x4f, 0x0a, 0x0b, 0x44, 0x6f,
+	0x63, 0x6b, 0x65, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2a, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x62, 0x6f, 0x62,
+	0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x6e, 0x66, 0x6f, 0x44, 0x6f, 0x63, 0x6b, 0x65, 0x72,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xb0, 0x01, 0x0a, 0x0e,
+	0x42, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x6e, 0x66, 0x6f, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x12,
+	0x0a, 0x04, 0x48, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x48, 0x61,
+	0x73, 0x68, 0x12, 0x3a, 0x0a, 0x07, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x62, 0x6f, 0x62, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x49,
+	0x6e, 0x66, 0x6f, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x2e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x1a, 0x4e,
+	0x0a, 0x0c, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10,
+	0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79,
+	0x12, 0x28, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x12, 0x2e, 0x62, 0x6f, 0x62, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x6e, 0x66, 0x6f, 0x46,
+	0x69, 0x6c, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x23,
+	0x0a, 0x0d, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x6e, 0x66, 0x6f, 0x46, 0x69, 0x6c, 0x65, 0x12,
+	0x12, 0x0a, 0x04, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x53,
+	0x69, 0x7a, 0x65, 0x22, 0x25, 0x0a, 0x0f, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x6e, 0x66, 0x6f,
+	0x44, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x48, 0x61, 0x73, 0x68, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x48, 0x61, 0x73, 0x68, 0x42, 0x1a, 0x5a, 0x18, 0x62, 0x6f,
+	0x62, 0x74, 0x61, 0x73, 0x6b, 0x2f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x69, 0x6e, 0x66, 0x6f, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -392,30 +501,36 @@ func file_buildinfo_proto_rawDescGZIP() []byte {
 	return file_buildinfo_proto_rawDescData
 }
 
-var file_buildinfo_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_buildinfo_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
 var file_buildinfo_proto_goTypes = []interface{}{
 	(*BuildInfo)(nil),       // 0: bob.BuildInfo
 	(*Meta)(nil),            // 1: bob.Meta
-	(*Targets)(nil),         // 2: bob.Targets
-	(*BuildInfoFiles)(nil),  // 3: bob.BuildInfoFiles
-	(*BuildInfoFile)(nil),   // 4: bob.BuildInfoFile
-	(*BuildInfoDocker)(nil), // 5: bob.BuildInfoDocker
-	nil,                     // 6: bob.Targets.DockerEntry
-	nil,                     // 7: bob.BuildInfoFiles.TargetsEntry
+	(*InputManifest)(nil),   // 2: bob.InputManifest
+	(*Targets)(nil),         // 3: bob.Targets
+	(*BuildInfoFiles)(nil),  // 4: bob.BuildInfoFiles
+	(*BuildInfoFile)(nil),   // 5: bob.BuildInfoFile
+	(*BuildInfoDocker)(nil), // 6: bob.BuildInfoDocker
+	nil,                     // 7: bob.InputManifest.FilesEntry
+	nil,                     // 8: bob.InputManifest.EnvEntry
+	nil,                     // 9: bob.Targets.DockerEntry
+	nil,                     // 10: bob.BuildInfoFiles.TargetsEntry
 }
 var file_buildinfo_proto_depIdxs = []int32{
-	2, // 0: bob.BuildInfo.Target:type_name -> bob.Targets
-	1, // 1: bob.BuildInfo.Meta:type_name -> bob.Meta
-	3, // 2: bob.Targets.Filesystem:type_name -> bob.BuildInfoFiles
-	6, // 3: bob.Targets.Docker:type_name -> bob.Targets.DockerEntry
-	7, // 4: bob.BuildInfoFiles.targets:type_name -> bob.BuildInfoFiles.TargetsEntry
-	5, // 5: bob.Targets.DockerEntry.value:type_name -> bob.BuildInfoDocker
-	4, // 6: bob.BuildInfoFiles.TargetsEntry.value:type_name -> bob.BuildInfoFile
-	7, // [7:7] is the sub-list for method output_type
-	7, // [7:7] is the sub-list for method input_type
-	7, // [7:7] is the sub-list for extension type_name
-	7, // [7:7] is the sub-list for extension extendee
-	0, // [0:7] is the sub-list for field type_name
+	3,  // 0: bob.BuildInfo.Target:type_name -> bob.Targets
+	1,  // 1: bob.BuildInfo.Meta:type_name -> bob.Meta
+	2,  // 2: bob.Meta.Inputs:type_name -> bob.InputManifest
+	7,  // 3: bob.InputManifest.Files:type_name -> bob.InputManifest.FilesEntry
+	8,  // 4: bob.InputManifest.Env:type_name -> bob.InputManifest.EnvEntry
+	4,  // 5: bob.Targets.Filesystem:type_name -> bob.BuildInfoFiles
+	9,  // 6: bob.Targets.Docker:type_name -> bob.Targets.DockerEntry
+	10, // 7: bob.BuildInfoFiles.targets:type_name -> bob.BuildInfoFiles.TargetsEntry
+	6,  // 8: bob.Targets.DockerEntry.value:type_name -> bob.BuildInfoDocker
+	5,  // 9: bob.BuildInfoFiles.TargetsEntry.value:type_name -> bob.BuildInfoFile
+	10, // [10:10] is the sub-list for method output_type
+	10, // [10:10] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_buildinfo_proto_init() }
@@ -449,7 +564,7 @@ func file_buildinfo_proto_init() {
 			}
 		}
 		file_buildinfo_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Targets); i {
+			switch v := v.(*InputManifest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -461,7 +576,7 @@ func file_buildinfo_proto_init() {
 			}
 		}
 		file_buildinfo_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*BuildInfoFiles); i {
+			switch v := v.(*Targets); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -473,7 +588,7 @@ func file_buildinfo_proto_init() {
 			}
 		}
 		file_buildinfo_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*BuildInfoFile); i {
+			switch v := v.(*BuildInfoFiles); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -485,6 +600,18 @@ func file_buildinfo_proto_init() {
 			}
 		}
 		file_buildinfo_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BuildInfoFile); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_buildinfo_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*BuildInfoDocker); i {
 			case 0:
 				return &v.state
@@ -503,7 +630,7 @@ func file_buildinfo_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_buildinfo_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   8,
+			NumMessages:   11,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
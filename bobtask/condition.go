@@ -0,0 +1,68 @@
+package bobtask
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/benchkram/bob/pkg/condition"
+	"github.com/benchkram/bob/pkg/envutil"
+	"github.com/benchkram/bob/pkg/file"
+	"github.com/benchkram/bob/pkg/usererror"
+)
+
+// SetVars sets the variables of the bobfile
+// the task is declared in.
+func (t *Task) SetVars(vars map[string]string) {
+	t.vars = vars
+}
+
+// Condition returns the `if` condition of the task, nil if it has none.
+func (t *Task) Condition() *condition.Condition {
+	return t.condition
+}
+
+// ConditionMet evaluates the `if` condition of the task against the
+// variables of its bobfile, the environment, the platform bob runs on
+// and the files in the task's directory.
+// The condition is only evaluated on the first call, usually before
+// the build starts, so files created by the build don't change it.
+// A task without condition is always executed.
+func (t *Task) ConditionMet() (bool, error) {
+	if t.condition == nil {
+		return true, nil
+	}
+	if t.conditionMet != nil {
+		return *t.conditionMet, nil
+	}
+
+	// Variables passed to the task override the host's environment.
+	env := make(map[string]string)
+	for _, e := range envutil.Merge(os.Environ(), t.env) {
+		pair := strings.SplitN(e, "=", 2)
+		if len(pair) == 2 {
+			env[pair[0]] = pair[1]
+		}
+	}
+
+	met, err := t.condition.Eval(condition.Context{
+		OS:   runtime.GOOS,
+		Arch: runtime.GOARCH,
+		Vars: t.vars,
+		Env:  env,
+		Exists: func(path string) bool {
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(t.dir, path)
+			}
+			return file.Exists(path)
+		},
+	})
+	if err != nil {
+		return false, usererror.Wrap(fmt.Errorf("task %s: %w", t.name, err))
+	}
+	t.conditionMet = &met
+
+	return met, nil
+}
@@ -14,4 +14,9 @@ var (
 	ErrAmbigousTargetDefinition = fmt.Errorf("ambigous target definition, can't have 'path' and 'image' directive on same target")
 
 	ErrAmbigousTargets = fmt.Errorf("ambigous targets detected")
+
+	ErrInvalidRetryPolicy = fmt.Errorf("invalid retry policy")
+	ErrTaskTimeout        = fmt.Errorf("task timed out")
+
+	ErrInvalidMatrix = fmt.Errorf("invalid matrix")
 )
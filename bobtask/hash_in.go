@@ -15,6 +15,7 @@ import (
 	"github.com/benchkram/bob/pkg/sliceutil"
 	"gopkg.in/yaml.v2"
 
+	"github.com/benchkram/bob/bobtask/buildinfo"
 	"github.com/benchkram/bob/bobtask/hash"
 	"github.com/benchkram/bob/pkg/filehash"
 )
@@ -33,13 +34,53 @@ func (t *Task) HashIn() (taskHash hash.In, err error) {
 	return t.computeInputHash()
 }
 
+// HashInCached returns the input hash if it has already been computed.
+func (t *Task) HashInCached() (_ hash.In, ok bool) {
+	if t.hashIn == nil {
+		return "", false
+	}
+	return *t.hashIn, true
+}
+
+// ClearHashIn drops the cached input hash.
+// The next call to HashIn() recomputes it.
+func (t *Task) ClearHashIn() {
+	t.hashIn = nil
+	t.inputManifest = nil
+}
+
+// InputManifest returns the digests of the files, environment and
+// task description the input hash is computed from.
+func (t *Task) InputManifest() (_ *buildinfo.InputManifest, err error) {
+	if t.inputManifest == nil || t.hashIn == nil {
+		_, err = t.computeInputHash()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return t.inputManifest, nil
+}
+
+// hashVersion is part of each input hash. Bump it when changing how
+// the input hash is computed, as all tasks are rebuilt once afterwards.
+//
+//	2: input files are hashed by their digests
+const hashVersion = "2"
+
 // computeInputHash computes a hash containing inputs, environment and the task description.
 func (t *Task) computeInputHash() (taskHash hash.In, err error) {
 	h := filehash.New()
+	manifest := buildinfo.NewInputManifest()
 
-	// Hash input files
+	err = h.AddBytes(bytes.NewBufferString(hashVersion))
+	if err != nil {
+		return taskHash, fmt.Errorf("failed to write hash version: %w", err)
+	}
+
+	// Hash input files, the hash of each file is added
+	// to allow reusing file hashes from the hash cache.
 	for _, f := range t.inputs {
-		err = h.AddFile(f)
+		fileHash, err := t.hashFile(f)
 		if err != nil {
 			if errors.Is(err, os.ErrPermission) {
 				t.addToSkippedInputs(f)
@@ -48,10 +89,25 @@ func (t *Task) computeInputHash() (taskHash hash.In, err error) {
 				return taskHash, fmt.Errorf("failed to hash file %q: %w", f, err)
 			}
 		}
+		err = h.AddBytes(bytes.NewReader(fileHash))
+		if err != nil {
+			return taskHash, fmt.Errorf("failed to write file hash: %w", err)
+		}
+		manifest.Files[f] = hex.EncodeToString(fileHash)
 	}
 
-	// Hash the public task description
-	description, err := yaml.Marshal(t)
+	// Hash the public task description.
+	// Tags only select tasks, the timeout and retry policy and the
+	// scheduling constraints only control the execution, they don't
+	// change the outcome of a build.
+	hashed := *t
+	hashed.Tags = nil
+	hashed.TimeoutDirty = ""
+	hashed.Retries = 0
+	hashed.RetryBackoffDirty = ""
+	hashed.Uses = nil
+	hashed.Exclusive = false
+	description, err := yaml.Marshal(&hashed)
 	if err != nil {
 		return taskHash, fmt.Errorf("failed to marshal task: %w", err)
 	}
@@ -59,6 +115,11 @@ func (t *Task) computeInputHash() (taskHash hash.In, err error) {
 	if err != nil {
 		return taskHash, fmt.Errorf("failed to write description hash: %w", err)
 	}
+	descriptionHash, err := filehash.HashBytes(bytes.NewBuffer(description))
+	if err != nil {
+		return taskHash, fmt.Errorf("failed to hash description: %w", err)
+	}
+	manifest.Description = hex.EncodeToString(descriptionHash)
 
 	// Hash the project name
 	err = h.AddBytes(bytes.NewBuffer([]byte(t.project)))
@@ -66,14 +127,26 @@ func (t *Task) computeInputHash() (taskHash hash.In, err error) {
 		return taskHash, fmt.Errorf("failed to write project name hash: %w", err)
 	}
 
-	// Hash the environment
-	env := filterOutWhitelistEnv(t.env)
+	// Hash the environment, except for variables passed from the host.
+	env := filterOutWhitelistEnv(t.env, t.passedEnv)
 	sort.Strings(env)
 	environment := strings.Join(env, ",")
 	err = h.AddBytes(bytes.NewBufferString(environment))
 	if err != nil {
 		return taskHash, fmt.Errorf("failed to write description hash: %w", err)
 	}
+	for _, e := range env {
+		pair := strings.SplitN(e, "=", 2)
+		value := ""
+		if len(pair) == 2 {
+			value = pair[1]
+		}
+		valueHash, err := filehash.HashBytes(bytes.NewBufferString(value))
+		if err != nil {
+			return taskHash, fmt.Errorf("failed to hash env var %q: %w", pair[0], err)
+		}
+		manifest.Env[pair[0]] = hex.EncodeToString(valueHash)
+	}
 
 	// Hash store paths
 	err = h.AddBytes(bytes.NewBufferString(strings.Join(t.storePaths, "")))
@@ -91,17 +164,29 @@ func (t *Task) computeInputHash() (taskHash hash.In, err error) {
 
 	// store hash for reuse
 	t.hashIn = &hashIn
+	t.inputManifest = manifest
 
 	boblog.Log.V(4).Info(fmt.Sprintf("Computed hash [h: %s] for task [t: %s], using [inputs:%d] input files ", t.hashIn.String(), t.Name(), len(t.inputs)))
 
 	return hashIn, nil
 }
 
-func filterOutWhitelistEnv(env []string) []string {
+// hashFile returns the hash of a file's content,
+// using the hash cache if available.
+func (t *Task) hashFile(f string) ([]byte, error) {
+	if t.hashCache != nil {
+		return t.hashCache.Hash(f)
+	}
+	return filehash.Hash(f)
+}
+
+// filterOutWhitelistEnv removes the whitelisted
+// variables and the variables passed from the host.
+func filterOutWhitelistEnv(env []string, passed []string) []string {
 	var result []string
 	for _, v := range env {
 		pair := strings.SplitN(v, "=", 2)
-		if sliceutil.Contains(global.EnvWhitelist, pair[0]) {
+		if sliceutil.Contains(global.EnvWhitelist, pair[0]) || sliceutil.Contains(passed, v) {
 			continue
 		}
 		result = append(result, v)
@@ -2,14 +2,11 @@ package bobtask
 
 import (
 	"fmt"
-	"log"
-	"os"
 	"path/filepath"
 	"sort"
 	"strings"
 
 	"github.com/benchkram/bob/bob/global"
-	"github.com/benchkram/bob/pkg/file"
 	"github.com/benchkram/bob/pkg/filepathutil"
 )
 
@@ -21,15 +18,52 @@ func (t *Task) SetInputs(inputs []string) {
 	t.inputs = inputs
 }
 
+// InputPatterns returns the input patterns as declared in the bobfile.
+func (t *Task) InputPatterns() []string {
+	return split(t.InputDirty)
+}
+
+// UpdateInputs reevaluates the inputs of the task against the filesystem.
+// Returns true in case the list of inputs changed.
+func (t *Task) UpdateInputs() (changed bool, err error) {
+	// Files might have been removed since the last evaluation.
+	clearResolveCache()
+
+	inputs, err := t.filteredInputs()
+	if err != nil {
+		return false, err
+	}
+
+	if len(inputs) != len(t.inputs) {
+		changed = true
+	} else {
+		for i := range inputs {
+			if inputs[i] != t.inputs[i] {
+				changed = true
+				break
+			}
+		}
+	}
+
+	t.inputs = inputs
+	return changed, nil
+}
+
 var (
-	defaultIgnores = fmt.Sprintf("!%s\n!%s",
-		global.BobWorkspaceFile,
-		filepath.Join(global.BobCacheDir, "*"),
-	)
+	// defaultIgnores are never considered as inputs.
+	defaultIgnores = []string{
+		"/" + global.BobWorkspaceFile,
+		global.BobCacheDir + "/",
+	}
 )
 
-// filteredInputs returns inputs filtered by ignores and file targets.
+// filteredInputs returns the files selected by the gitignore-style
+// input patterns of the task. Files ignored by `.bobignore` files,
+// `.gitignore` files (if enabled) and file & dir targets are filtered.
 // Calls sanitize on the result.
+//
+// Paths are resolved relative to the task's directory,
+// the working directory of the process is not used.
 func (t *Task) filteredInputs() ([]string, error) {
 
 	wd, err := filepath.Abs(t.dir)
@@ -37,126 +71,51 @@ func (t *Task) filteredInputs() ([]string, error) {
 		return nil, err
 	}
 
-	owd, err := os.Getwd()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get current working directory: %w", err)
-	}
-	if err := os.Chdir(wd); err != nil {
-		return nil, fmt.Errorf("failed to change current working directory to %s: %w", t.dir, err)
-	}
-	defer func() {
-		if err := os.Chdir(owd); err != nil {
-			log.Printf("failed to change current working directory back to %s: %v\n", owd, err)
-		}
-	}()
-
-	inputDirty := fmt.Sprintf("%s\n%s", t.InputDirty, defaultIgnores)
-
-	// Determine inputs and files to be ignored
-	var inputs []string
-	var ignores []string
-	for _, input := range unique(split(inputDirty)) {
-		// Ignore starts with !
-		if strings.HasPrefix(input, "!") {
-			input = strings.TrimPrefix(input, "!")
-			list, err := filepathutil.ListRecursive(input)
-			if err != nil {
-				return nil, fmt.Errorf("failed to list input: %w", err)
-			}
-
-			ignores = append(ignores, list...)
-			continue
-		}
-
-		list, err := filepathutil.ListRecursive(input)
-		if err != nil {
-			return nil, fmt.Errorf("failed to list input: %w", err)
+	// abs returns the absolute path of a path relative to the task's directory.
+	abs := func(path string) string {
+		if filepath.IsAbs(path) {
+			return path
 		}
+		return filepath.Join(wd, path)
+	}
 
-		inputs = append(inputs, list...)
+	opts := filepathutil.ListOptions{
+		Ignores:     defaultIgnores,
+		IgnoreFiles: []string{global.BobIgnoreFileName},
+	}
+	if t.useGitignore {
+		opts.IgnoreFiles = append(opts.IgnoreFiles, global.GitIgnoreFileName)
 	}
 
-	// Also ignore file & dir targets stored in the same directory
+	// Ignore file & dir targets stored in the same directory
 	if t.target != nil {
 		for _, path := range t.target.FilesystemEntriesRawPlain() {
-			if file.Exists(path) {
-				info, err := os.Stat(path)
-				if err != nil {
-					return nil, fmt.Errorf("failed to stat %s: %w", path, err)
-				}
-				if info.IsDir() {
-					list, err := filepathutil.ListRecursive(path)
-					if err != nil {
-						return nil, fmt.Errorf("failed to list input: %w", err)
-					}
-					ignores = append(ignores, list...)
-					continue
-				}
-				ignores = append(ignores, t.target.FilesystemEntriesRawPlain()...)
-			}
+			opts.Skip = append(opts.Skip, abs(path))
 		}
 	}
 
 	// Also ignore additional ignores found during aggregation.
 	// Usually the targets of child tasks.
 	for _, path := range t.InputAdditionalIgnores {
-		if file.Exists(path) {
-			info, err := os.Stat(path)
-			if err != nil {
-				return nil, fmt.Errorf("failed to stat %s: %w", path, err)
-			}
-
-			if info.IsDir() {
-				list, err := filepathutil.ListRecursive(path)
-				if err != nil {
-					return nil, fmt.Errorf("failed to list input: %w", err)
-				}
-				ignores = append(ignores, list...)
-				continue
-			}
-		}
-		ignores = append(ignores, path)
+		opts.Skip = append(opts.Skip, abs(path))
 	}
 
-	inputs = unique(inputs)
-	ignores = unique(ignores)
-
-	// Filter
-	filteredInputs := make([]string, 0, len(inputs))
-	for _, input := range inputs {
-		input = filepath.Clean(input)
-
-		var isIgnored bool
-		for _, ignore := range ignores {
-			if input == filepath.Clean(ignore) {
-				isIgnored = true
-				break
-			}
-		}
-
-		if !isIgnored {
-			filteredInputs = append(filteredInputs, input)
-		}
+	inputs, err := filepathutil.List(wd, split(t.InputDirty), opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list input: %w", err)
 	}
 
 	sanitizedInputs, err := t.sanitizeInputs(
-		filteredInputs,
+		inputs,
 		optimisationOptions{wd: wd},
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to sanitize inputs: %w", err)
 	}
 
-	sortedInputs := sanitizedInputs
 	sort.Strings(sanitizedInputs)
 
-	// fmt.Println("Inputs:", inputs)
-	// fmt.Println("Ignores:", ignores)
-	// fmt.Println("Filtered:", filteredInputs)
-	// fmt.Println("Sanitized:", sanitizedInputs)
-	// fmt.Println("Sorted:", sortedInputs)
-
-	return sortedInputs, nil
+	return sanitizedInputs, nil
 }
 
 func unique(ss []string) []string {
@@ -0,0 +1,166 @@
+package bobtask
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeFiles(t *testing.T, dir string, files ...string) {
+	for _, f := range files {
+		path := filepath.Join(dir, f)
+		assert.Nil(t, os.MkdirAll(filepath.Dir(path), 0775))
+		assert.Nil(t, os.WriteFile(path, []byte(f), 0664))
+	}
+}
+
+func TestFilterInputsWithoutChdir(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, filepath.Join(root, "one"), "main.go", "sub/lib.go", "ignored.txt")
+	writeFiles(t, filepath.Join(root, "two"), "other.go")
+
+	wd, err := os.Getwd()
+	assert.Nil(t, err)
+
+	one := Make()
+	one.SetDir(filepath.Join(root, "one"))
+	one.InputDirty = "*\n!ignored.txt"
+
+	two := Make()
+	two.SetDir(filepath.Join(root, "two"))
+	two.InputDirty = "*"
+
+	tm := Map{"one": one, "two": two}
+	assert.Nil(t, tm.FilterInputs())
+
+	assert.Equal(t, []string{
+		filepath.Join(root, "one", "main.go"),
+		filepath.Join(root, "one", "sub", "lib.go"),
+	}, tm["one"].inputs)
+	assert.Equal(t, []string{
+		filepath.Join(root, "two", "other.go"),
+	}, tm["two"].inputs)
+
+	// the working directory of the process is never changed
+	after, err := os.Getwd()
+	assert.Nil(t, err)
+	assert.Equal(t, wd, after)
+}
+
+func TestFilterInputsOutsideOfTaskDir(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, "outside.go", "task/main.go")
+
+	task := Make()
+	task.SetDir(filepath.Join(root, "task"))
+	task.InputDirty = "../outside.go"
+
+	_, err := task.filteredInputs()
+	assert.NotNil(t, err)
+}
+
+func TestFilterInputsGitignorePatterns(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root,
+		"main.go",
+		"main_test.go",
+		"build/out.go",
+		"src/lib.go",
+		"src/lib_test.go",
+		"src/main.go",
+		"src/testdata/keep_test.go",
+		"src/vendor/dep.go",
+		"docs/build",
+	)
+
+	type test struct {
+		name     string
+		input    string
+		expected []string
+	}
+
+	tests := []test{
+		{
+			name:     "unanchored patterns match at any depth",
+			input:    "main.go",
+			expected: []string{"main.go", "src/main.go"},
+		},
+		{
+			name:     "anchored patterns match relative to the task directory",
+			input:    "/main.go\n./src/lib.go",
+			expected: []string{"main.go", "src/lib.go"},
+		},
+		{
+			name:     "double asterisk matches any number of directories",
+			input:    "src/**/*_test.go",
+			expected: []string{"src/lib_test.go", "src/testdata/keep_test.go"},
+		},
+		{
+			name:     "directory only patterns don't match files",
+			input:    "build/",
+			expected: []string{"build/out.go"},
+		},
+		{
+			name:  "negation re-includes files",
+			input: "src\n!*_test.go\n!src/vendor/\nsrc/testdata/keep_test.go",
+			expected: []string{
+				"src/lib.go",
+				"src/main.go",
+				"src/testdata/keep_test.go",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		task := Make()
+		task.SetDir(root)
+		task.InputDirty = tc.input
+
+		inputs, err := task.filteredInputs()
+		assert.Nil(t, err, tc.name)
+
+		expected := make([]string, 0, len(tc.expected))
+		for _, f := range tc.expected {
+			expected = append(expected, filepath.Join(root, f))
+		}
+		assert.Equal(t, expected, inputs, tc.name)
+	}
+}
+
+func TestFilterInputsIgnoreFiles(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root,
+		"main.go",
+		"debug.log",
+		"assets/large.bin",
+		"sub/lib.go",
+		"sub/gen.go",
+		"sub/generated/types.go",
+	)
+	assert.Nil(t, os.WriteFile(filepath.Join(root, ".bobignore"), []byte("# comment\n*.log\n/assets/\n"), 0664))
+	assert.Nil(t, os.WriteFile(filepath.Join(root, "sub", ".bobignore"), []byte("generated/\n"), 0664))
+	assert.Nil(t, os.WriteFile(filepath.Join(root, ".gitignore"), []byte("gen.go\n"), 0664))
+
+	task := Make()
+	task.SetDir(root)
+	task.InputDirty = "*\n!.*ignore"
+
+	inputs, err := task.filteredInputs()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(root, "main.go"),
+		filepath.Join(root, "sub", "gen.go"),
+		filepath.Join(root, "sub", "lib.go"),
+	}, inputs)
+
+	// .gitignore files are only honored when enabled
+	task.SetUseGitignore(true)
+	inputs, err = task.filteredInputs()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(root, "main.go"),
+		filepath.Join(root, "sub", "lib.go"),
+	}, inputs)
+}
@@ -0,0 +1,50 @@
+package bobtask
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/logrusorgru/aurora"
+
+	"github.com/benchkram/bob/pkg/boblog"
+	"github.com/benchkram/bob/pkg/tasklog"
+)
+
+// ReplayLog prints the output stored for the current input hash of the task.
+// Returns false in case no output was stored.
+func (t *Task) ReplayLog(namePad int) (bool, error) {
+	if t.logStore == nil {
+		return false, nil
+	}
+
+	hashIn, err := t.HashIn()
+	if err != nil {
+		return false, err
+	}
+
+	log, err := t.logStore.Get(t.name, hashIn.String())
+	if err != nil {
+		if errors.Is(err, tasklog.ErrLogDoesNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, t.PrintOutput(namePad, log)
+}
+
+// PrintOutput prints the output of a run line by line.
+func (t *Task) PrintOutput(namePad int, output []byte) error {
+	s := bufio.NewScanner(bytes.NewReader(output))
+	for s.Scan() {
+		t.printLogLine(namePad, s.Text())
+	}
+	return s.Err()
+}
+
+// printLogLine prints a single line of output of the task.
+func (t *Task) printLogLine(namePad int, line string) {
+	boblog.Log.V(1).Info(fmt.Sprintf("%-*s\t  %s", namePad, t.ColoredName(), aurora.Faint(line)))
+}
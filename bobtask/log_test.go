@@ -0,0 +1,36 @@
+package bobtask
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/benchkram/bob/pkg/tasklog"
+)
+
+func TestRunStoresLog(t *testing.T) {
+	store := tasklog.New(t.TempDir())
+
+	task := Make()
+	task.name = "build"
+	task.SetDir(t.TempDir())
+	task.cmds = []string{"echo first", "echo second >&2"}
+	task.WithLogStore(store)
+
+	replayed, err := task.ReplayLog(0)
+	assert.Nil(t, err)
+	assert.False(t, replayed, "no log should be stored before the first run")
+
+	assert.Nil(t, task.Run(context.Background(), 0, nil))
+
+	hashIn, err := task.HashIn()
+	assert.Nil(t, err)
+	log, err := store.Get("build", hashIn.String())
+	assert.Nil(t, err)
+	assert.Equal(t, "first\nsecond\n", string(log))
+
+	replayed, err = task.ReplayLog(0)
+	assert.Nil(t, err)
+	assert.True(t, replayed)
+}
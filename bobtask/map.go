@@ -4,10 +4,13 @@ import (
 	"bytes"
 	"fmt"
 	"path/filepath"
+	"runtime"
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/benchkram/errz"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/benchkram/bob/pkg/boberror"
 	"github.com/benchkram/bob/pkg/multilinecmd"
@@ -47,14 +50,34 @@ func (tm Map) Walk(root string, parentLevel string, fn func(taskname string, _ T
 	return nil
 }
 
+// FilterInputs determines the inputs of all tasks concurrently.
 func (tm Map) FilterInputs() (err error) {
 	defer errz.Recover(&err)
 
+	var mu sync.Mutex
+	filtered := make(map[string][]string, len(tm))
+
+	g := errgroup.Group{}
+	g.SetLimit(runtime.NumCPU())
 	for key, task := range tm {
-		inputs, err := task.filteredInputs()
-		errz.Fatal(err)
-		task.inputs = inputs
+		key, task := key, task
+		g.Go(func() error {
+			inputs, err := task.filteredInputs()
+			if err != nil {
+				return err
+			}
 
+			mu.Lock()
+			filtered[key] = inputs
+			mu.Unlock()
+			return nil
+		})
+	}
+	errz.Fatal(g.Wait())
+
+	for key, inputs := range filtered {
+		task := tm[key]
+		task.inputs = inputs
 		tm[key] = task
 	}
 
@@ -74,6 +97,12 @@ func (tm Map) Sanitize() (err error) {
 		task.cmds = multilinecmd.Split(task.CmdDirty)
 		task.rebuild = task.sanitizeRebuild(task.RebuildDirty)
 
+		task.timeout, task.retryBackoff, err = task.sanitizeRetryPolicy()
+		errz.Fatal(err)
+
+		task.condition, err = task.sanitizeCondition()
+		errz.Fatal(err)
+
 		tm[key] = task
 	}
 
@@ -0,0 +1,233 @@
+package bobtask
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/benchkram/bob/pkg/usererror"
+)
+
+// Matrix is a list of variables, each with the values a task
+// is expanded with. The order of the variables is kept as
+// declared to name the instances, e.g.
+//
+//	matrix:
+//	  GOOS: [linux, darwin]
+//	  GOARCH: [amd64, arm64]
+//
+// expands to `build[linux,amd64]`, `build[linux,arm64]`, ...
+type Matrix []MatrixVariable
+
+type MatrixVariable struct {
+	Name   string
+	Values []string
+}
+
+func (m *Matrix) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind != yaml.MappingNode {
+		return fmt.Errorf("%w: expected a map of variables near line %d", ErrInvalidMatrix, value.Line)
+	}
+
+	matrix := Matrix{}
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		var v MatrixVariable
+		v.Name = value.Content[i].Value
+
+		err := value.Content[i+1].Decode(&v.Values)
+		if err != nil {
+			return fmt.Errorf("%w: expected a list of values for `%s` near line %d", ErrInvalidMatrix, v.Name, value.Content[i+1].Line)
+		}
+		matrix = append(matrix, v)
+	}
+
+	*m = matrix
+	return nil
+}
+
+func (m Matrix) MarshalYAML() (interface{}, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, v := range m {
+		values := &yaml.Node{}
+		err := values.Encode(v.Values)
+		if err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: v.Name}, values)
+	}
+	return node, nil
+}
+
+// combinations returns all combinations of the matrix values
+// as key=value pairs, the first variable varying slowest.
+func (m Matrix) combinations() [][]string {
+	combinations := [][]string{{}}
+	for _, v := range m {
+		var next [][]string
+		for _, c := range combinations {
+			for _, value := range v.Values {
+				combination := append(append([]string{}, c...), v.Name+"="+value)
+				next = append(next, combination)
+			}
+		}
+		combinations = next
+	}
+	return combinations
+}
+
+func (m Matrix) validate(taskname string) error {
+	if len(m) == 0 {
+		return usererror.Wrap(fmt.Errorf("%w: task %s has an empty matrix", ErrInvalidMatrix, taskname))
+	}
+
+	names := make(map[string]bool)
+	for _, v := range m {
+		if v.Name == "" || strings.Contains(v.Name, "=") {
+			return usererror.Wrap(fmt.Errorf("%w: task %s has an invalid variable name %q", ErrInvalidMatrix, taskname, v.Name))
+		}
+		if names[v.Name] {
+			return usererror.Wrap(fmt.Errorf("%w: task %s declares `%s` twice", ErrInvalidMatrix, taskname, v.Name))
+		}
+		names[v.Name] = true
+
+		if len(v.Values) == 0 {
+			return usererror.Wrap(fmt.Errorf("%w: task %s has no values for `%s`", ErrInvalidMatrix, taskname, v.Name))
+		}
+		for _, value := range v.Values {
+			// values become part of the task name
+			if value == "" || strings.ContainsAny(value, "/,[] ") {
+				return usererror.Wrap(fmt.Errorf("%w: task %s has an invalid value %q for `%s`", ErrInvalidMatrix, taskname, value, v.Name))
+			}
+		}
+	}
+
+	return nil
+}
+
+// MatrixEnv returns the key=value pairs of a matrix instance,
+// nil if the task is not a matrix instance.
+func (t *Task) MatrixEnv() []string {
+	return t.matrixEnv
+}
+
+// MatrixName returns the name of the matrix task an instance
+// was expanded from, e.g. `build` for `build[linux,amd64]`.
+// Returns the task's name if it is not a matrix instance.
+func (t *Task) MatrixName() string {
+	if len(t.matrixEnv) == 0 {
+		return t.name
+	}
+	return t.name[:strings.LastIndex(t.name, "[")]
+}
+
+// instance creates the task of a matrix combination.
+// `${NAME}` in inputs and targets is replaced by the value of the variable.
+func (t Task) instance(name string, combination []string) Task {
+	instance := t
+	instance.Matrix = nil
+	instance.matrixEnv = combination
+
+	values := make([]string, 0, len(combination))
+	replacements := make([]string, 0, 2*len(combination))
+	for _, c := range combination {
+		pair := strings.SplitN(c, "=", 2)
+		values = append(values, pair[1])
+		replacements = append(replacements, "${"+pair[0]+"}", pair[1])
+	}
+	r := strings.NewReplacer(replacements...)
+
+	instance.name = name + "[" + strings.Join(values, ",") + "]"
+	instance.InputDirty = r.Replace(t.InputDirty)
+
+	switch td := t.TargetDirty.(type) {
+	case string:
+		instance.TargetDirty = r.Replace(td)
+	case map[string]interface{}:
+		target := make(map[string]interface{}, len(td))
+		for k, v := range td {
+			if s, ok := v.(string); ok {
+				v = r.Replace(s)
+			}
+			target[k] = v
+		}
+		instance.TargetDirty = target
+	}
+
+	return instance
+}
+
+// ExpandMatrices replaces each task with a matrix by its instances,
+// one for each combination of the matrix values.
+// Targets of the instances are parsed again as they
+// might contain the values of the matrix.
+func (tm Map) ExpandMatrices() error {
+	var names []string
+	for name, task := range tm {
+		if task.Matrix != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		task := tm[name]
+		err := task.Matrix.validate(name)
+		if err != nil {
+			return err
+		}
+
+		delete(tm, name)
+		for i, combination := range task.Matrix.combinations() {
+			instance := task.instance(name, combination)
+			instance.matrixIndex = i
+			err = instance.parseTargets()
+			if err != nil {
+				return err
+			}
+			if _, exists := tm[instance.name]; exists {
+				return usererror.Wrap(fmt.Errorf("%w: task %s already exists", ErrInvalidMatrix, instance.name))
+			}
+			tm[instance.name] = instance
+		}
+	}
+
+	return nil
+}
+
+// Matrices returns the instances of each matrix task by name
+// of the matrix task, in the order of the matrix combinations.
+func (tm Map) Matrices() map[string][]string {
+	matrices := make(map[string][]string)
+	for _, task := range tm {
+		if len(task.matrixEnv) > 0 {
+			name := task.MatrixName()
+			matrices[name] = append(matrices[name], task.name)
+		}
+	}
+	for _, instances := range matrices {
+		sort.Slice(instances, func(i, j int) bool {
+			return tm[instances[i]].matrixIndex < tm[instances[j]].matrixIndex
+		})
+	}
+	return matrices
+}
+
+// ExpandMatrixNames replaces the names of matrix tasks by the names
+// of their instances, e.g. to depend on all instances of a matrix.
+func ExpandMatrixNames(names []string, matrices map[string][]string) []string {
+	if len(matrices) == 0 {
+		return names
+	}
+
+	expanded := make([]string, 0, len(names))
+	for _, name := range names {
+		if instances, ok := matrices[name]; ok {
+			expanded = append(expanded, instances...)
+			continue
+		}
+		expanded = append(expanded, name)
+	}
+	return expanded
+}
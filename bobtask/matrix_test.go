@@ -0,0 +1,95 @@
+package bobtask
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+var withMatrix = `
+build:
+  cmd: go build -o bin/app-${GOOS}-${GOARCH}
+  input: "*.go"
+  target: bin/app-${GOOS}-${GOARCH}
+  matrix:
+    GOOS: [linux, darwin]
+    GOARCH: [amd64, arm64]
+test:
+  cmd: go test
+  target:
+    path: report-${PG}.xml
+  matrix:
+    PG: [13, 14]
+`
+
+func TestExpandMatrices(t *testing.T) {
+	var tm Map
+	err := yaml.Unmarshal([]byte(withMatrix), &tm)
+	assert.Nil(t, err)
+
+	err = tm.ExpandMatrices()
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{
+		"build[darwin,amd64]",
+		"build[darwin,arm64]",
+		"build[linux,amd64]",
+		"build[linux,arm64]",
+		"test[13]",
+		"test[14]",
+	}, tm.KeysSortedAlpabethically())
+
+	instance := tm["build[darwin,arm64]"]
+	assert.Equal(t, "build[darwin,arm64]", instance.Name())
+	assert.Equal(t, "build", instance.MatrixName())
+	assert.Equal(t, []string{"GOOS=darwin", "GOARCH=arm64"}, instance.MatrixEnv())
+	assert.Equal(t, "bin/app-darwin-arm64", instance.TargetDirty)
+	assert.Equal(t, "*.go", instance.InputDirty)
+	assert.Equal(t, "go build -o bin/app-${GOOS}-${GOARCH}", instance.CmdDirty, "cmd uses the environment")
+	assert.Nil(t, instance.Matrix)
+
+	assert.Equal(t, map[string]interface{}{"path": "report-14.xml"}, tm["test[14]"].TargetDirty)
+
+	assert.Equal(t, map[string][]string{
+		"build": {"build[linux,amd64]", "build[linux,arm64]", "build[darwin,amd64]", "build[darwin,arm64]"},
+		"test":  {"test[13]", "test[14]"},
+	}, tm.Matrices())
+
+	assert.Equal(t,
+		[]string{"lint", "test[13]", "test[14]"},
+		ExpandMatrixNames([]string{"lint", "test"}, tm.Matrices()),
+	)
+}
+
+func TestExpandMatricesInvalid(t *testing.T) {
+	for _, matrix := range []string{
+		`{}`,
+		`{GOOS: []}`,
+		`{GOOS: [linux/amd64]}`,
+		`{GOOS: ["linux,darwin"]}`,
+	} {
+		task := Task{}
+		err := yaml.Unmarshal([]byte(matrix), &task.Matrix)
+		assert.Nil(t, err, matrix)
+
+		tm := Map{"build": task}
+		err = tm.ExpandMatrices()
+		assert.True(t, errors.Is(err, ErrInvalidMatrix), matrix)
+	}
+
+	var task Task
+	err := yaml.Unmarshal([]byte("matrix: [linux, darwin]"), &task)
+	assert.True(t, errors.Is(err, ErrInvalidMatrix))
+}
+
+func TestMatrixMarshalYAML(t *testing.T) {
+	var task Task
+	err := yaml.Unmarshal([]byte("matrix:\n  GOOS: [linux]\n  GOARCH: [amd64, arm64]\n"), &task)
+	assert.Nil(t, err)
+
+	out, err := yaml.Marshal(task.Matrix)
+	assert.Nil(t, err)
+	assert.Equal(t, "GOOS:\n    - linux\nGOARCH:\n    - amd64\n    - arm64\n", string(out))
+}
@@ -3,15 +3,16 @@ package bobtask
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+	"time"
 
-	"github.com/benchkram/bob/pkg/boblog"
 	"github.com/benchkram/bob/pkg/envutil"
 	"github.com/benchkram/bob/pkg/nix"
 	"github.com/benchkram/bob/pkg/usererror"
-	"github.com/logrusorgru/aurora"
 	"mvdan.cc/sh/expand"
 	"mvdan.cc/sh/interp"
 	"mvdan.cc/sh/syntax"
@@ -19,9 +20,28 @@ import (
 	"github.com/benchkram/errz"
 )
 
-func (t *Task) Run(ctx context.Context, namePad int) (err error) {
+// killTimeout is the grace period given to running commands
+// to terminate after the context was canceled before they are killed.
+const killTimeout = 5 * time.Second
+
+// outputTailLines is the number of output lines
+// shown in the summary of a failed task.
+const outputTailLines = 10
+
+// Run executes the commands of the task.
+// Commands are canceled when the task's timeout is exceeded.
+//
+// The output of the commands is written to output line by line.
+// It's printed right away in case output is nil.
+func (t *Task) Run(ctx context.Context, namePad int, output io.Writer) (err error) {
 	defer errz.Recover(&err)
 
+	if t.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, t.timeout)
+		defer cancel()
+	}
+
 	env := t.Env()
 	if len(t.storePaths) > 0 {
 		nixShellEnv, err := nix.BuildEnvironment(t.dependencies)
@@ -29,6 +49,16 @@ func (t *Task) Run(ctx context.Context, namePad int) (err error) {
 		env = envutil.Merge(nixShellEnv, env)
 	}
 
+	// the combined output of all commands is written to the log store
+	var log io.WriteCloser
+	if t.logStore != nil {
+		hashIn, err := t.HashIn()
+		errz.Fatal(err)
+		log, err = t.logStore.Create(t.name, hashIn.String())
+		errz.Fatal(err)
+		defer log.Close()
+	}
+
 	for _, run := range t.cmds {
 		p, err := syntax.NewParser().Parse(strings.NewReader(run), "")
 		if err != nil {
@@ -43,6 +73,10 @@ func (t *Task) Run(ctx context.Context, namePad int) (err error) {
 		s := bufio.NewScanner(pr)
 		s.Split(bufio.ScanLines)
 
+		// tail holds the last lines of output
+		// to be shown in case of a failure.
+		var tail []string
+
 		done := make(chan bool)
 
 		go func() {
@@ -52,7 +86,19 @@ func (t *Task) Run(ctx context.Context, namePad int) (err error) {
 					return
 				}
 
-				boblog.Log.V(1).Info(fmt.Sprintf("%-*s\t  %s", namePad, t.ColoredName(), aurora.Faint(s.Text())))
+				if output != nil {
+					_, _ = fmt.Fprintln(output, s.Text())
+				} else {
+					t.printLogLine(namePad, s.Text())
+				}
+				if log != nil {
+					_, _ = fmt.Fprintln(log, s.Text())
+				}
+
+				tail = append(tail, s.Text())
+				if len(tail) > outputTailLines {
+					tail = tail[1:]
+				}
 			}
 
 			done <- true
@@ -66,12 +112,21 @@ func (t *Task) Run(ctx context.Context, namePad int) (err error) {
 		)
 
 		errz.Fatal(err)
+		r.KillTimeout = killTimeout
 
 		err = r.Run(ctx, p)
 		if err != nil {
 			pw.Close()
 			<-done
-			return usererror.Wrapm(err, "shell command execute error")
+			if t.timeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return usererror.Wrap(fmt.Errorf("%w after %s", ErrTaskTimeout, t.timeout)).
+					WithSummary(t.failureSummary(run, nil, tail))
+			}
+			if errors.Is(ctx.Err(), context.Canceled) {
+				return ctx.Err()
+			}
+			return usererror.Wrapm(err, fmt.Sprintf("task %s failed", t.name)).
+				WithSummary(t.failureSummary(run, err, tail))
 		}
 
 		// wait for the reader to finish after closing the write pipe
@@ -81,3 +136,30 @@ func (t *Task) Run(ctx context.Context, namePad int) (err error) {
 
 	return nil
 }
+
+// failureSummary describes a failed command
+// together with the last lines of its output.
+func (t *Task) failureSummary(cmd string, err error, tail []string) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "  task:        %s\n", t.name)
+	fmt.Fprintf(&b, "  command:     %s\n", cmd)
+
+	var exitStatus interp.ExitStatus
+	var shellExitStatus interp.ShellExitStatus
+	switch {
+	case errors.As(err, &exitStatus):
+		fmt.Fprintf(&b, "  exit status: %d\n", exitStatus)
+	case errors.As(err, &shellExitStatus):
+		fmt.Fprintf(&b, "  exit status: %d\n", shellExitStatus)
+	}
+
+	if len(tail) > 0 {
+		fmt.Fprintf(&b, "  output (last %d lines):\n", len(tail))
+		for _, line := range tail {
+			fmt.Fprintf(&b, "    %s\n", line)
+		}
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
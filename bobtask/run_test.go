@@ -0,0 +1,53 @@
+package bobtask
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/benchkram/bob/pkg/usererror"
+)
+
+func TestRunFailureSummary(t *testing.T) {
+	task := Make()
+	task.name = "test"
+	task.SetDir(t.TempDir())
+	task.cmds = []string{
+		"echo before",
+		"echo first && echo second && exit 3",
+		"echo never",
+	}
+
+	err := task.Run(context.Background(), 0, nil)
+	assert.NotNil(t, err)
+
+	var uerr *usererror.E
+	assert.True(t, errors.As(err, &uerr))
+	assert.Equal(t, "task test failed", uerr.Msg())
+	assert.Equal(t, ""+
+		"  task:        test\n"+
+		"  command:     echo first && echo second && exit 3\n"+
+		"  exit status: 3\n"+
+		"  output (last 2 lines):\n"+
+		"    first\n"+
+		"    second",
+		uerr.Summary(),
+	)
+}
+
+func TestRunFailureSummaryTail(t *testing.T) {
+	task := Make()
+	task.name = "test"
+	task.SetDir(t.TempDir())
+	task.cmds = []string{"for i in 1 2 3 4 5 6 7 8 9 10 11 12; do echo line$i; done; false"}
+
+	err := task.Run(context.Background(), 0, nil)
+
+	var uerr *usererror.E
+	assert.True(t, errors.As(err, &uerr))
+	assert.Contains(t, uerr.Summary(), "exit status: 1")
+	assert.Contains(t, uerr.Summary(), "output (last 10 lines):\n    line3\n")
+	assert.NotContains(t, uerr.Summary(), "line2\n")
+}
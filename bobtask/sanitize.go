@@ -6,21 +6,26 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
 	"errors"
+
+	"github.com/benchkram/bob/pkg/condition"
+	"github.com/benchkram/bob/pkg/usererror"
 )
 
 type optimisationOptions struct {
-	// wd is the current working directory
-	// to avoid calls to os.Getwd.
+	// wd is the working directory of the task
+	// relative paths are resolved against.
 	wd string
 }
 
 // sanitizeInputs assures that inputs are only cosidered when they are inside the project dir.
-// Needs to be called with the current working directory set to the tasks working directory.
+// Relative inputs are resolved against the task's working directory passed in opts.
 func (t *Task) sanitizeInputs(inputs []string, opts optimisationOptions) ([]string, error) {
 
-	projectRoot, err := resolve(".", optimisationOptions{})
+	projectRoot, err := resolve(opts.wd, optimisationOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve project root %q: %w", t.dir, err)
 	}
@@ -60,9 +65,21 @@ type absolutePathOrError struct {
 }
 
 // absPathMap caches already resolved absolute paths.
-// FIXME: in case of asynchronous calls this should be a sync map
-// or use a lock.
 var absPathMap = make(map[string]absolutePathOrError, 10000)
+var absPathMapMu sync.RWMutex
+
+// clearResolveCache drops all cached absolute paths.
+func clearResolveCache() {
+	absPathMapMu.Lock()
+	defer absPathMapMu.Unlock()
+	absPathMap = make(map[string]absolutePathOrError, 10000)
+}
+
+func cacheResolved(abs string, aoe absolutePathOrError) {
+	absPathMapMu.Lock()
+	defer absPathMapMu.Unlock()
+	absPathMap[abs] = aoe
+}
 
 // resolve is a very basic implementation only preventing the inclusion of files outside of the project.
 // It is very likely still possible to include other files with malicious intention.
@@ -84,7 +101,9 @@ func resolve(path string, opts optimisationOptions) (_ string, err error) {
 
 	}
 
+	absPathMapMu.RLock()
 	aoe, ok := absPathMap[abs]
+	absPathMapMu.RUnlock()
 	if ok {
 		return aoe.abs, aoe.err
 	}
@@ -99,14 +118,14 @@ func resolve(path string, opts optimisationOptions) (_ string, err error) {
 		sym, err := filepath.EvalSymlinks(abs)
 		if err != nil {
 			a := absolutePathOrError{"", fmt.Errorf("failed to follow symlink of %q: %w", abs, err)}
-			absPathMap[abs] = a
+			cacheResolved(abs, a)
 			return a.abs, a.err
 		}
-		absPathMap[abs] = absolutePathOrError{abs: sym, err: nil}
+		cacheResolved(abs, absolutePathOrError{abs: sym, err: nil})
 		return sym, nil
 	}
 
-	absPathMap[abs] = absolutePathOrError{abs: abs, err: nil}
+	cacheResolved(abs, absolutePathOrError{abs: abs, err: nil})
 	return abs, nil
 }
 
@@ -122,6 +141,44 @@ func (t *Task) sanitizeRebuild(s string) RebuildType {
 	}
 }
 
+// sanitizeRetryPolicy parses the timeout and retry backoff durations.
+func (t *Task) sanitizeRetryPolicy() (timeout, backoff time.Duration, _ error) {
+	if t.TimeoutDirty != "" {
+		d, err := time.ParseDuration(t.TimeoutDirty)
+		if err != nil || d <= 0 {
+			return 0, 0, usererror.Wrap(fmt.Errorf("%w: invalid timeout %q for task %s", ErrInvalidRetryPolicy, t.TimeoutDirty, t.name))
+		}
+		timeout = d
+	}
+
+	if t.Retries < 0 {
+		return 0, 0, usererror.Wrap(fmt.Errorf("%w: retries must not be negative for task %s", ErrInvalidRetryPolicy, t.name))
+	}
+
+	if t.RetryBackoffDirty != "" {
+		d, err := time.ParseDuration(t.RetryBackoffDirty)
+		if err != nil || d < 0 {
+			return 0, 0, usererror.Wrap(fmt.Errorf("%w: invalid retry backoff %q for task %s", ErrInvalidRetryPolicy, t.RetryBackoffDirty, t.name))
+		}
+		backoff = d
+	}
+
+	return timeout, backoff, nil
+}
+
 func isOutsideOfProject(root, f string) bool {
 	return !strings.HasPrefix(f, root)
 }
+
+// sanitizeCondition parses the `if` condition, nil if the task has none.
+func (t *Task) sanitizeCondition() (*condition.Condition, error) {
+	if strings.TrimSpace(t.IfDirty) == "" {
+		return nil, nil
+	}
+
+	c, err := condition.Parse(t.IfDirty)
+	if err != nil {
+		return nil, usererror.Wrap(fmt.Errorf("task %s: %w", t.name, err))
+	}
+	return c, nil
+}
@@ -9,6 +9,15 @@ import (
 	"github.com/benchkram/bob/pkg/buildinfostore"
 )
 
+// DeclaredTargets returns the targets as declared in the bobfile.
+// Filesystem targets are relative to the umbrella bobfile.
+func (t *Task) DeclaredTargets() (filesystem []string, dockerImages []string) {
+	if t.target == nil {
+		return nil, nil
+	}
+	return t.target.FilesystemEntriesRaw(), t.target.DockerImages()
+}
+
 // Target takes care of populating the targets members correctly.
 // It returns a nil in case of a non existing target and a nil error.
 func (t *Task) Target() (empty target.Target, _ error) {
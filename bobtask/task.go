@@ -2,15 +2,20 @@ package bobtask
 
 import (
 	"strings"
+	"time"
 
 	"github.com/benchkram/bob/pkg/nix"
 	"github.com/logrusorgru/aurora"
 
+	"github.com/benchkram/bob/bobtask/buildinfo"
 	"github.com/benchkram/bob/bobtask/hash"
 	"github.com/benchkram/bob/bobtask/target"
 	"github.com/benchkram/bob/pkg/buildinfostore"
+	"github.com/benchkram/bob/pkg/condition"
 	"github.com/benchkram/bob/pkg/dockermobyutil"
+	"github.com/benchkram/bob/pkg/filehash"
 	"github.com/benchkram/bob/pkg/store"
+	"github.com/benchkram/bob/pkg/tasklog"
 )
 
 type RebuildType string
@@ -49,6 +54,52 @@ type Task struct {
 	RebuildDirty string `yaml:"rebuild,omitempty"`
 	rebuild      RebuildType
 
+	// TimeoutDirty is the maximum duration the commands of
+	// the task are allowed to run, e.g. `10m`.
+	TimeoutDirty string `yaml:"timeout,omitempty"`
+	timeout      time.Duration
+
+	// Retries is the number of times a failed task is retried.
+	Retries int `yaml:"retries,omitempty"`
+
+	// RetryBackoffDirty is the delay before the first retry, e.g. `5s`.
+	// The delay doubles with each subsequent retry.
+	RetryBackoffDirty string `yaml:"retryBackoff,omitempty"`
+	retryBackoff      time.Duration
+
+	// Uses lists the resource pools the task occupies while running.
+	Uses []string `yaml:"uses,omitempty"`
+
+	// Exclusive tasks never run in parallel with other tasks.
+	Exclusive bool `yaml:"exclusive,omitempty"`
+
+	// Tags are used to select tasks on the command line, e.g. `bob build --tag test`.
+	Tags []string `yaml:"tags,omitempty"`
+
+	// EnvDirty are environment variables of the task, taking precedence
+	// over the variables of the bobfile. Their values are part of the input hash.
+	EnvDirty map[string]string `yaml:"env,omitempty"`
+
+	// PassEnv lists environment variables passed from the host to the task.
+	// Their values are not part of the input hash.
+	PassEnv []string `yaml:"passEnv,omitempty"`
+
+	// IfDirty is a condition, the task is skipped when it evaluates to false.
+	// See pkg/condition for the syntax.
+	IfDirty   string `yaml:"if,omitempty"`
+	condition *condition.Condition
+	// conditionMet is the result of the condition,
+	// evaluated once to be the same throughout a build.
+	conditionMet *bool
+
+	// Matrix expands the task into an instance for each
+	// combination of the matrix values, e.g. `build[linux,amd64]`.
+	Matrix Matrix `yaml:"matrix,omitempty"`
+	// matrixEnv holds the key=value pairs of a matrix instance.
+	matrixEnv []string
+	// matrixIndex orders the instances of a matrix.
+	matrixIndex int
+
 	// name is the name of the task
 	// TODO: Make this public to allow yaml.Marshal to add this to the task hash?!?
 	name string
@@ -63,9 +114,20 @@ type Task struct {
 	// when the task is executed.
 	env []string
 
+	// passedEnv holds the key=value pairs of env
+	// which were passed from the host.
+	passedEnv []string
+
+	// vars are the variables of the bobfile
+	// the task is declared in.
+	vars map[string]string
+
 	// hashIn stores the `In` has for reuse
 	hashIn *hash.In
 
+	// inputManifest holds the digests hashIn was computed from.
+	inputManifest *buildinfo.InputManifest
+
 	// local store for artifacts
 	local store.Store
 
@@ -75,6 +137,16 @@ type Task struct {
 	// buildInfoStore stores buildinfos.
 	buildInfoStore buildinfostore.Store
 
+	// hashCache avoids rehashing unchanged input files.
+	hashCache *filehash.Cache
+
+	// logStore stores the output of each run.
+	logStore tasklog.Store
+
+	// useGitignore excludes files ignored by
+	// `.gitignore` files from the inputs.
+	useGitignore bool
+
 	// color is used to color the task's name on the terminal
 	color aurora.Color
 
@@ -163,6 +235,36 @@ func (t *Task) IsValidDecoration() bool {
 	if t.RebuildDirty != "" {
 		return false
 	}
+	if t.TimeoutDirty != "" {
+		return false
+	}
+	if t.Retries != 0 {
+		return false
+	}
+	if t.RetryBackoffDirty != "" {
+		return false
+	}
+	if len(t.Uses) > 0 {
+		return false
+	}
+	if t.Exclusive {
+		return false
+	}
+	if len(t.Tags) > 0 {
+		return false
+	}
+	if len(t.EnvDirty) > 0 {
+		return false
+	}
+	if len(t.PassEnv) > 0 {
+		return false
+	}
+	if t.IfDirty != "" {
+		return false
+	}
+	if len(t.Matrix) > 0 {
+		return false
+	}
 	if len(t.DependenciesDirty) > 0 {
 		return false
 	}
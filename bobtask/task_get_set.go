@@ -2,11 +2,14 @@ package bobtask
 
 import (
 	"path/filepath"
+	"time"
 
 	"github.com/benchkram/bob/pkg/buildinfostore"
 	"github.com/benchkram/bob/pkg/dockermobyutil"
+	"github.com/benchkram/bob/pkg/filehash"
 	"github.com/benchkram/bob/pkg/nix"
 	"github.com/benchkram/bob/pkg/store"
+	"github.com/benchkram/bob/pkg/tasklog"
 	"github.com/logrusorgru/aurora"
 )
 
@@ -51,6 +54,16 @@ func (t *Task) SetEnv(env []string) {
 	t.env = env
 }
 
+// SetPassedEnv sets the variables which got their value
+// from the host. They are not part of the input hash.
+func (t *Task) SetPassedEnv(env []string) {
+	t.passedEnv = env
+}
+
+func (t *Task) SetUseGitignore(use bool) {
+	t.useGitignore = use
+}
+
 func (t *Task) Dependencies() []nix.Dependency {
 	return t.dependencies
 }
@@ -101,7 +114,27 @@ func (t *Task) WithBuildinfoStore(s buildinfostore.Store) *Task {
 	return t
 }
 
+func (t *Task) WithLogStore(s tasklog.Store) *Task {
+	t.logStore = s
+	return t
+}
+
+func (t *Task) WithHashCache(c *filehash.Cache) *Task {
+	t.hashCache = c
+	return t
+}
+
 func (t *Task) WithDockerRegistryClient(c dockermobyutil.RegistryClient) *Task {
 	t.dockerRegistryClient = c
 	return t
 }
+
+// Timeout of the task's commands, zero means no timeout.
+func (t *Task) Timeout() time.Duration {
+	return t.timeout
+}
+
+// RetryBackoff is the delay before the first retry of a failed task.
+func (t *Task) RetryBackoff() time.Duration {
+	return t.retryBackoff
+}
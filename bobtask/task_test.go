@@ -2,6 +2,7 @@ package bobtask
 
 import (
 	"testing"
+	"time"
 
 	"gopkg.in/yaml.v3"
 
@@ -63,3 +64,31 @@ func TestTaskUnmarshalYAMLWithBothDependsOn(t *testing.T) {
 	err := yaml.Unmarshal([]byte(withBoth), &task)
 	assert.EqualError(t, err, "both `dependson` and `dependsOn` nodes detected near line 2")
 }
+
+var withRetryPolicy = `
+cmd: go test ./...
+timeout: 10m
+retries: 3
+retryBackoff: 5s
+`
+
+func TestTaskSanitizeRetryPolicy(t *testing.T) {
+	var task Task
+	err := yaml.Unmarshal([]byte(withRetryPolicy), &task)
+	assert.Nil(t, err)
+
+	timeout, backoff, err := task.sanitizeRetryPolicy()
+	assert.Nil(t, err)
+	assert.Equal(t, 10*time.Minute, timeout)
+	assert.Equal(t, 5*time.Second, backoff)
+	assert.Equal(t, 3, task.Retries)
+
+	task.TimeoutDirty = "forever"
+	_, _, err = task.sanitizeRetryPolicy()
+	assert.ErrorIs(t, err, ErrInvalidRetryPolicy)
+
+	task.TimeoutDirty = ""
+	task.Retries = -1
+	_, _, err = task.sanitizeRetryPolicy()
+	assert.ErrorIs(t, err, ErrInvalidRetryPolicy)
+}
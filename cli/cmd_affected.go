@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/benchkram/bob/bob"
+	"github.com/benchkram/bob/pkg/boblog"
+	"github.com/benchkram/bob/pkg/usererror"
+	"github.com/benchkram/errz"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	affectedCmd.Flags().String("since", "", "Git revision to compare the worktree against, e.g. origin/main")
+	affectedCmd.Flags().Bool("json", false, "Print the changed files and affected tasks as json")
+	rootCmd.AddCommand(affectedCmd)
+}
+
+var affectedCmd = &cobra.Command{
+	Use:   "affected --since <git-ref>",
+	Short: "List the tasks affected by changes since a git revision",
+	Long: `List the tasks affected by changes since a git revision.
+Changes are compared across all repositories of the workspace and include uncommitted and untracked files.
+A task is affected when one of its inputs or its bobfile changed, or when it depends on an affected task.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		since, err := cmd.Flags().GetString("since")
+		errz.Fatal(err)
+		if since == "" {
+			boblog.Log.Error(fmt.Errorf("missing --since"), "a git revision is required")
+			os.Exit(1)
+		}
+
+		asJSON, err := cmd.Flags().GetBool("json")
+		errz.Fatal(err)
+
+		runAffected(since, asJSON)
+	},
+}
+
+func runAffected(since string, asJSON bool) {
+	b, err := bob.Bob()
+	boblog.Log.Error(err, "Unable to initialise bob")
+
+	affected, err := b.Affected(since)
+	if err != nil {
+		if errors.As(err, &usererror.Err) {
+			boblog.Log.UserError(err)
+			exit(1)
+		}
+		errz.Fatal(err)
+	}
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(affected)
+		errz.Fatal(err)
+		return
+	}
+
+	for _, taskname := range affected.Tasks {
+		fmt.Println(taskname)
+	}
+	for _, runname := range affected.Runs {
+		fmt.Println(runname)
+	}
+}
@@ -10,10 +10,12 @@ import (
 	"syscall"
 
 	"github.com/benchkram/errz"
+	"github.com/logrusorgru/aurora"
 	"github.com/spf13/cobra"
 
 	"github.com/benchkram/bob/bob"
 	"github.com/benchkram/bob/bob/global"
+	"github.com/benchkram/bob/bob/playbook"
 	"github.com/benchkram/bob/pkg/boblog"
 	"github.com/benchkram/bob/pkg/usererror"
 )
@@ -46,12 +48,90 @@ var buildCmd = &cobra.Command{
 		noPull, err := cmd.Flags().GetBool("no-pull")
 		errz.Fatal(err)
 
-		taskname := global.DefaultBuildTask
+		watch, err := cmd.Flags().GetBool("watch")
+		errz.Fatal(err)
+
+		keepGoing, err := cmd.Flags().GetBool("keep-going")
+		errz.Fatal(err)
+
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		errz.Fatal(err)
+
+		replayLogs, err := cmd.Flags().GetBool("replay-logs")
+		errz.Fatal(err)
+
+		output, err := cmd.Flags().GetString("output")
+		errz.Fatal(err)
+		outputMode, err := playbook.ParseOutputMode(output)
+		if err != nil {
+			boblog.Log.Error(err, "unsupported output mode")
+			os.Exit(1)
+		}
+
+		reportTrace, err := cmd.Flags().GetString("report-trace")
+		errz.Fatal(err)
+
+		reportJUnit, err := cmd.Flags().GetString("report-junit")
+		errz.Fatal(err)
+
+		opts := []bob.Option{
+			bob.WithCachingEnabled(!noCache),
+			bob.WithInsecure(allowInsecure),
+			bob.WithEnvVariables(parseEnvVarsFlag(flagEnvVars)),
+			bob.WithMaxParallel(maxParallel),
+			bob.WithPushEnabled(enablePush),
+			bob.WithPullEnabled(!noPull),
+			bob.WithKeepGoing(keepGoing),
+			bob.WithReplayLogs(replayLogs),
+			bob.WithOutputMode(outputMode),
+			bob.WithTraceReport(reportTrace),
+			bob.WithJUnitReport(reportJUnit),
+		}
+
+		events, err := cmd.Flags().GetString("events")
+		errz.Fatal(err)
+		if events != "" && events != "json" {
+			boblog.Log.Error(fmt.Errorf("unsupported events format %q", events), "events must be json")
+			os.Exit(1)
+		}
+		eventsFile, err := cmd.Flags().GetString("events-file")
+		errz.Fatal(err)
+		// closed by runBuild once the build is done.
+		var eventsOut *os.File
+		if events == "json" {
+			// events are kept apart from the output on stdout
+			// to be parsable.
+			w := os.Stderr
+			if eventsFile != "" {
+				eventsOut, err = os.Create(eventsFile)
+				if err != nil {
+					boblog.Log.Error(err, "unable to create events file")
+					os.Exit(1)
+				}
+				w = eventsOut
+			}
+			opts = append(opts, bob.WithSubscriber(playbook.JSONSubscriber(w)))
+		}
+
+		affectedSince, err := cmd.Flags().GetString("affected-since")
+		errz.Fatal(err)
+		if affectedSince != "" && len(args) > 0 {
+			boblog.Log.Error(fmt.Errorf("tasks can't be combined with --affected-since"), "invalid arguments")
+			os.Exit(1)
+		}
+
+		filter := tagFilter(cmd)
+		if !filter.IsEmpty() && len(args) > 0 {
+			boblog.Log.Error(fmt.Errorf("tasks can't be combined with --tag or --exclude-tag"), "invalid arguments")
+			os.Exit(1)
+		}
+
+		tasknames := []string{global.DefaultBuildTask}
 		if len(args) > 0 {
-			taskname = args[0]
+			tasknames = args
 		}
 
-		runBuild(taskname, noCache, allowInsecure, enablePush, noPull, flagEnvVars, maxParallel)
+		runBuild(tasknames, affectedSince, filter, watch, dryRun, opts, eventsOut)
 	},
 	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		tasks, err := getBuildTasks()
@@ -68,25 +148,30 @@ var buildListCmd = &cobra.Command{
 	Short: "ls",
 	Long:  ``,
 	Run: func(cmd *cobra.Command, args []string) {
-		runBuildList()
+		runBuildList(tagFilter(cmd))
 	},
 }
 
-func runBuild(taskname string, noCache, allowInsecure, enablePush, noPull bool, flagEnvVars []string, maxParallel int) {
+// runBuild builds the tasks, or only prints what would be built on a dry run.
+// The tasks are selected by the tag filter and changes since
+// `affectedSince` if given. The events file is closed after the build.
+func runBuild(tasknames []string, affectedSince string, filter bob.TagFilter, watch, dryRun bool, opts []bob.Option, eventsFile *os.File) {
 	var exitCode int
 	defer func() {
 		exit(exitCode)
 	}()
 	defer errz.Recover()
+	if eventsFile != nil {
+		defer func() {
+			err := eventsFile.Close()
+			if err != nil {
+				boblog.Log.Error(err, "unable to write events file")
+				exitCode = 1
+			}
+		}()
+	}
 
-	b, err := bob.Bob(
-		bob.WithCachingEnabled(!noCache),
-		bob.WithInsecure(allowInsecure),
-		bob.WithEnvVariables(parseEnvVarsFlag(flagEnvVars)),
-		bob.WithMaxParallel(maxParallel),
-		bob.WithPushEnabled(enablePush),
-		bob.WithPullEnabled(!noPull),
-	)
+	b, err := bob.Bob(opts...)
 	if err != nil {
 		exitCode = 1
 		errz.Fatal(err)
@@ -100,10 +185,41 @@ func runBuild(taskname string, noCache, allowInsecure, enablePush, noPull bool,
 		signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 
 		<-stop
+		boblog.Log.V(1).Info(aurora.Yellow("Canceling build, press Ctrl-C again to exit immediately").String())
 		cancel()
+
+		<-stop
+		exit(1)
 	}()
 
-	err = b.Build(ctx, taskname)
+	// build exactly the selected tasks
+	if affectedSince != "" || !filter.IsEmpty() {
+		tasknames, err = selectBuildTasks(b, affectedSince, filter)
+		if err != nil {
+			exitCode = 1
+			if errors.As(err, &usererror.Err) {
+				boblog.Log.UserError(err)
+				return
+			}
+			errz.Fatal(err)
+		}
+		if len(tasknames) == 0 {
+			fmt.Println("No tasks selected")
+			return
+		}
+	}
+
+	if dryRun {
+		var decisions []playbook.RebuildDecision
+		decisions, err = b.DryRun(ctx, tasknames...)
+		if err == nil {
+			printRebuildDecisions(decisions)
+		}
+	} else if watch {
+		err = b.Watch(ctx, tasknames...)
+	} else {
+		err = b.Build(ctx, tasknames...)
+	}
 	if err != nil {
 		exitCode = 1
 		if errors.As(err, &usererror.Err) {
@@ -114,11 +230,78 @@ func runBuild(taskname string, noCache, allowInsecure, enablePush, noPull bool,
 	}
 }
 
-func runBuildList() {
+// printRebuildDecisions prints what a build would do with each task.
+func printRebuildDecisions(decisions []playbook.RebuildDecision) {
+	var namePad int
+	for _, d := range decisions {
+		if len(d.TaskName) > namePad {
+			namePad = len(d.TaskName)
+		}
+	}
+
+	fmt.Println()
+	fmt.Println(aurora.Bold("Dry run, no task is executed"))
+	for _, d := range decisions {
+		var decision string
+		switch {
+		case d.Skipped:
+			decision = aurora.Faint("skipped").String()
+		case d.Cause == "":
+			decision = aurora.Green("cached").String()
+		case d.ArtifactSource == playbook.ArtifactSourceLocal:
+			decision = fmt.Sprintf("%s\t%s", d.Cause.String(), aurora.Green("load artifact from local store"))
+		case d.ArtifactSource == playbook.ArtifactSourceRemote:
+			decision = fmt.Sprintf("%s\t%s", d.Cause.String(), aurora.Green("pull artifact from remote store"))
+		default:
+			decision = fmt.Sprintf("%s\t%s", d.Cause.String(), aurora.Yellow("rebuild"))
+		}
+		fmt.Printf("  %-*s\t%s\n", namePad, d.TaskName, decision)
+	}
+	fmt.Println()
+}
+
+// selectBuildTasks returns the build tasks matching the tag filter,
+// limited to the tasks affected by changes since the revision if given.
+func selectBuildTasks(b *bob.B, affectedSince string, filter bob.TagFilter) ([]string, error) {
+	tasknames, err := b.GetBuildTasks(filter)
+	if err != nil || affectedSince == "" {
+		return tasknames, err
+	}
+
+	affected, err := b.Affected(affectedSince)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := make(map[string]bool, len(tasknames))
+	for _, taskname := range tasknames {
+		selected[taskname] = true
+	}
+
+	tasknames = []string{}
+	for _, taskname := range affected.Tasks {
+		if selected[taskname] {
+			tasknames = append(tasknames, taskname)
+		}
+	}
+	return tasknames, nil
+}
+
+// tagFilter returns the tag filter given by the `--tag` and `--exclude-tag` flags.
+func tagFilter(cmd *cobra.Command) bob.TagFilter {
+	tags, err := cmd.Flags().GetStringSlice("tag")
+	errz.Fatal(err)
+	excludeTags, err := cmd.Flags().GetStringSlice("exclude-tag")
+	errz.Fatal(err)
+
+	return bob.TagFilter{Tags: tags, ExcludeTags: excludeTags}
+}
+
+func runBuildList(filter bob.TagFilter) {
 	b, err := bob.Bob()
 	boblog.Log.Error(err, "Unable to initialize bob")
 
-	tasks, err := b.GetBuildTasks()
+	tasks, err := b.GetBuildTasks(filter)
 	boblog.Log.Error(err, "Unable to aggregate bob file")
 
 	for _, t := range tasks {
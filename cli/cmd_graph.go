@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/benchkram/bob/bob"
+	"github.com/benchkram/bob/pkg/boblog"
+	"github.com/benchkram/bob/pkg/taskgraph"
+	"github.com/benchkram/bob/pkg/usererror"
+	"github.com/benchkram/errz"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	graphCmd.Flags().String("format", "dot", "Output format, one of dot, mermaid, json")
+	graphCmd.Flags().Bool("state", false, "Color tasks by their rebuild state")
+	rootCmd.AddCommand(graphCmd)
+}
+
+var graphCmd = &cobra.Command{
+	Use:   "graph [task]",
+	Short: "Print the dependency graph of tasks",
+	Long: `Print the dependency graph of tasks with their inputs, targets and nix dependencies.
+Without a task the graph contains all build and run tasks.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		f, err := cmd.Flags().GetString("format")
+		errz.Fatal(err)
+		format, err := taskgraph.ParseFormat(f)
+		if err != nil {
+			boblog.Log.Error(err, "unsupported format")
+			os.Exit(1)
+		}
+		state, err := cmd.Flags().GetBool("state")
+		errz.Fatal(err)
+
+		runGraph(format, state, args...)
+	},
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		buildTasks, err := getBuildTasks()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		runTasks, err := getRunTasks()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+
+		return append(buildTasks, runTasks...), cobra.ShellCompDirectiveDefault
+	},
+}
+
+func runGraph(format taskgraph.Format, state bool, tasknames ...string) {
+	b, err := bob.Bob()
+	boblog.Log.Error(err, "Unable to initialise bob")
+
+	g, err := b.Graph(context.Background(), state, tasknames...)
+	if err != nil {
+		if errors.As(err, &usererror.Err) {
+			boblog.Log.UserError(err)
+			exit(1)
+		}
+		errz.Fatal(err)
+	}
+
+	err = g.Write(os.Stdout, format)
+	errz.Fatal(err)
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benchkram/bob/pkg/nix"
+	"github.com/benchkram/bob/pkg/taskgraph"
+)
+
+func TestGraphJSONWithState(t *testing.T) {
+	// runGraph exits the process on a missing nix installation.
+	if !nix.IsInstalled() {
+		t.Skip("nix is not installed")
+	}
+	t.Setenv("HOME", t.TempDir())
+
+	dir := t.TempDir()
+	// a version mismatch prints a warning while the state is determined
+	bobfile := `
+version: 1.0.0
+build:
+  build:
+    input: main.go
+    cmd: touch app
+    target: app
+    dependsOn: [lib]
+  lib:
+    cmd: echo lib
+`
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "bob.yaml"), []byte(bobfile), 0664))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main"), 0664))
+
+	wd, err := os.Getwd()
+	require.Nil(t, err)
+	require.Nil(t, os.Chdir(dir))
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	// anything but the graph written to stdout breaks the json
+	out, err := os.Create(filepath.Join(t.TempDir(), "stdout"))
+	require.Nil(t, err)
+	stdout := os.Stdout
+	os.Stdout = out
+	t.Cleanup(func() {
+		os.Stdout = stdout
+		_ = out.Close()
+	})
+
+	runGraph(taskgraph.FormatJSON, true)
+
+	b, err := os.ReadFile(out.Name())
+	assert.Nil(t, err)
+
+	var g taskgraph.Graph
+	assert.Nil(t, json.Unmarshal(b, &g), string(b))
+	assert.Len(t, g.Nodes, 2)
+	for _, n := range g.Nodes {
+		assert.NotEmpty(t, n.State, n.Name)
+	}
+}
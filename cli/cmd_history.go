@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/benchkram/bob/bob"
+	"github.com/benchkram/bob/pkg/boblog"
+	"github.com/benchkram/bob/pkg/buildhistory"
+	"github.com/benchkram/bob/pkg/usererror"
+	"github.com/benchkram/errz"
+	"github.com/logrusorgru/aurora"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	historyCmd.Flags().IntP("limit", "n", 20, "Number of recent builds to list")
+	historyCmd.AddCommand(historyShowCmd)
+	rootCmd.AddCommand(historyCmd)
+}
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List recent builds",
+	Long:  ``,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		limit, err := cmd.Flags().GetInt("limit")
+		errz.Fatal(err)
+
+		runHistory(limit)
+	},
+}
+
+var historyShowCmd = &cobra.Command{
+	Use:   "show <id>",
+	Short: "Show the outcome of each task of a build",
+	Long:  ``,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			boblog.Log.UserError(usererror.Wrapm(err, "invalid build id"))
+			exit(1)
+		}
+
+		runHistoryShow(id)
+	},
+}
+
+func runHistory(limit int) {
+	b, err := bob.Bob()
+	boblog.Log.Error(err, "Unable to initialise bob")
+
+	runs, err := b.History()
+	errz.Fatal(err)
+
+	if len(runs) == 0 {
+		fmt.Println("No builds recorded")
+		return
+	}
+
+	if limit > 0 && len(runs) > limit {
+		runs = runs[len(runs)-limit:]
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSTARTED\tTASKS\tDURATION\tCACHED\tRESULT")
+	// most recent build first
+	for i := len(runs) - 1; i >= 0; i-- {
+		run := runs[i]
+
+		result := aurora.Green("ok").String()
+		if run.Failed() {
+			result = aurora.Red("failed").String()
+		}
+
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n",
+			run.ID,
+			run.Start.Format("2006-01-02 15:04:05"),
+			strings.Join(run.Tasks, ","),
+			run.Duration.Round(time.Millisecond),
+			cacheHitRate(run),
+			result,
+		)
+	}
+	_ = w.Flush()
+}
+
+func runHistoryShow(id int) {
+	b, err := bob.Bob()
+	boblog.Log.Error(err, "Unable to initialise bob")
+
+	run, err := b.HistoryRun(id)
+	if err != nil {
+		if errors.Is(err, buildhistory.ErrRunDoesNotExist) {
+			boblog.Log.UserError(usererror.Wrapm(err, fmt.Sprintf("build %d not found", id)))
+			exit(1)
+		}
+		errz.Fatal(err)
+	}
+
+	fmt.Printf("Build %d of %s started %s, took %s\n",
+		run.ID, strings.Join(run.Tasks, ","), run.Start.Format("2006-01-02 15:04:05"), run.Duration.Round(time.Millisecond))
+	if run.Failed() {
+		fmt.Printf("%s %s\n", aurora.Red("failed:"), run.Error)
+	}
+	fmt.Println()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TASK\tSTATE\tCAUSE\tDURATION\tARTIFACT\tINPUT HASH")
+	for _, t := range run.TaskRecords {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			t.Name,
+			t.State,
+			orDash(t.RebuildCause),
+			t.Duration.Round(time.Millisecond),
+			orDash(t.ArtifactSource),
+			orDash(t.InputHash),
+		)
+	}
+	_ = w.Flush()
+}
+
+// cacheHitRate formats the number of cached tasks of a build.
+func cacheHitRate(run *buildhistory.Run) string {
+	total := len(run.TaskRecords)
+	if total == 0 {
+		return "-"
+	}
+	cached := run.Cached()
+	return fmt.Sprintf("%d/%d (%d%%)", cached, total, cached*100/total)
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/benchkram/bob/bob"
 	"github.com/benchkram/bob/pkg/boblog"
+	"github.com/benchkram/bob/pkg/envutil"
 	"github.com/benchkram/bob/pkg/nix"
 	"github.com/benchkram/bob/pkg/usererror"
 	"github.com/benchkram/errz"
@@ -24,6 +25,7 @@ func init() {
 
 	inspectCmd.AddCommand(inputCmd)
 	inspectCmd.AddCommand(envCmd)
+	inspectCmd.AddCommand(whyCmd)
 	inspectArtifactCmd.AddCommand(inspectArtifactListCmd)
 	inspectCmd.AddCommand(inspectArtifactCmd)
 	rootCmd.AddCommand(inspectCmd)
@@ -74,7 +76,9 @@ func runEnv(taskname string) {
 	taskEnv, err := nix.BuildEnvironment(task.Dependencies())
 	errz.Fatal(err)
 
-	for _, e := range taskEnv {
+	// the environment of the task takes precedence
+	// as it does when the task is executed.
+	for _, e := range envutil.Merge(taskEnv, task.Env()) {
 		println(e)
 	}
 }
@@ -175,3 +179,72 @@ func runInspectInputs(taskname string) {
 
 	fmt.Printf("Task %s has %d inputs\n", taskname, len(inputs))
 }
+
+var whyCmd = &cobra.Command{
+	Use:   "why",
+	Short: "Explain why a task needs to be rebuilt",
+	Args:  cobra.ExactArgs(1),
+	Long: `Compares the inputs, environment and definition of a task
+with the ones recorded by its last successful build.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		taskname := args[0]
+		runInspectWhy(taskname)
+	},
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		tasks, err := getBuildTasks()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+
+		return tasks, cobra.ShellCompDirectiveDefault
+	},
+}
+
+func runInspectWhy(taskname string) {
+	b, err := bob.Bob()
+	boblog.Log.Error(err, "Unable to initialise bob")
+
+	explanation, err := b.Why(taskname)
+	if err != nil {
+		if errors.As(err, &usererror.Err) {
+			boblog.Log.UserError(err)
+			exit(1)
+		}
+		errz.Log(err)
+		exit(1)
+	}
+
+	if explanation.UpToDate {
+		fmt.Printf("Task %s is up to date\n", taskname)
+		return
+	}
+
+	if explanation.Previous == nil {
+		fmt.Printf("Task %s has no recorded successful build\n", taskname)
+		return
+	}
+
+	diff := explanation.Diff
+	if diff.Empty() {
+		// inputs not covered by the manifest changed, e.g. nix store paths.
+		fmt.Printf("Task %s needs a rebuild, no changed files, env vars or definition found\n", taskname)
+		return
+	}
+
+	fmt.Printf("Task %s changed since its last successful build:\n", taskname)
+	for _, f := range diff.AddedFiles {
+		fmt.Printf("  %s %s\n", aurora.Green("added:   "), f)
+	}
+	for _, f := range diff.RemovedFiles {
+		fmt.Printf("  %s %s\n", aurora.Red("removed: "), f)
+	}
+	for _, f := range diff.ModifiedFiles {
+		fmt.Printf("  %s %s\n", aurora.Yellow("modified:"), f)
+	}
+	for _, e := range diff.ChangedEnv {
+		fmt.Printf("  %s %s\n", aurora.Yellow("env:     "), e)
+	}
+	if diff.DescriptionChanged {
+		fmt.Printf("  %s task definition\n", aurora.Yellow("modified:"))
+	}
+}
@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/benchkram/bob/bob"
+	"github.com/benchkram/bob/pkg/boblog"
+	"github.com/benchkram/bob/pkg/tasklog"
+	"github.com/benchkram/bob/pkg/usererror"
+	"github.com/benchkram/errz"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(logsCmd)
+}
+
+var logsCmd = &cobra.Command{
+	Use:   "logs <task>",
+	Short: "Show the output of the last run of a task",
+	Long:  ``,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runLogs(args[0])
+	},
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		tasks, err := getBuildTasks()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+
+		return tasks, cobra.ShellCompDirectiveDefault
+	},
+}
+
+func runLogs(taskname string) {
+	b, err := bob.Bob()
+	boblog.Log.Error(err, "Unable to initialise bob")
+
+	log, err := b.Logs(taskname)
+	if err != nil {
+		if errors.Is(err, tasklog.ErrLogDoesNotExist) {
+			boblog.Log.UserError(usererror.Wrapm(err, fmt.Sprintf("no output stored for task %s", taskname)))
+			exit(1)
+		}
+		errz.Fatal(err)
+	}
+
+	_, err = os.Stdout.Write(log)
+	errz.Fatal(err)
+}
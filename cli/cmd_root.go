@@ -9,6 +9,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/benchkram/bob/bob"
+	"github.com/benchkram/bob/bob/playbook"
 	"github.com/benchkram/bob/pkg/boblog"
 )
 
@@ -44,6 +45,8 @@ func init() {
 	runCmd.Flags().Bool("no-cache", false, "Set to true to not use cache")
 	runCmd.Flags().Bool("insecure", false, "Set to true to use http instead of https when accessing a remote artifact store")
 	runCmd.Flags().StringSliceVar(&flagEnvVars, "env", []string{}, "Set environment variables to run task")
+	runListCmd.Flags().StringSlice("tag", []string{}, "List the tasks with one of the given tags")
+	runListCmd.Flags().StringSlice("exclude-tag", []string{}, "Omit the tasks with one of the given tags")
 	runCmd.AddCommand(runListCmd)
 	rootCmd.AddCommand(runCmd)
 
@@ -53,8 +56,22 @@ func init() {
 	buildCmd.Flags().Bool("push", false, "Set to true to push artifacts to remote store")
 	buildCmd.Flags().Bool("no-pull", false, "Set to true to disable artifacts download from remote store")
 	buildCmd.Flags().Bool("insecure", false, "Set to true to use http instead of https when accessing a remote artifact store")
+	buildCmd.Flags().Bool("watch", false, "Keep running and rebuild when inputs change")
+	buildCmd.Flags().Bool("keep-going", false, "Continue building independent tasks after a task failed")
+	buildCmd.Flags().String("output", string(playbook.OutputStream), "How to print the output of tasks, supported: stream, grouped, failed-only")
+	buildCmd.Flags().Bool("replay-logs", false, "Print the stored output of tasks which didn't need to be rebuild")
+	buildCmd.Flags().String("affected-since", "", "Build the tasks affected by changes since the given git revision")
+	buildCmd.Flags().StringSlice("tag", []string{}, "Build the tasks with one of the given tags")
+	buildCmd.Flags().StringSlice("exclude-tag", []string{}, "Omit the tasks with one of the given tags")
+	buildCmd.Flags().Bool("dry-run", false, "Print which tasks would be rebuild and why, without executing them")
+	buildCmd.Flags().String("events", "", "Print a machine readable event for each task state change to stderr, supported: json")
+	buildCmd.Flags().String("events-file", "", "Write the events to the given file instead of stderr")
+	buildCmd.Flags().String("report-trace", "", "Write the timing of the build as Chrome trace to the given file")
+	buildCmd.Flags().String("report-junit", "", "Write the result of each task as JUnit XML to the given file")
 	buildCmd.Flags().IntP("jobs", "j", runtime.NumCPU(), "Maximum number of parallel started jobs")
 	buildCmd.Flags().StringSliceVar(&flagEnvVars, "env", []string{}, "Set environment variables to build task")
+	buildListCmd.Flags().StringSlice("tag", []string{}, "List the tasks with one of the given tags")
+	buildListCmd.Flags().StringSlice("exclude-tag", []string{}, "Omit the tasks with one of the given tags")
 	buildCmd.AddCommand(buildListCmd)
 	rootCmd.AddCommand(buildCmd)
 
@@ -13,15 +13,15 @@ var runListCmd = &cobra.Command{
 	Short: "ls",
 	Long:  ``,
 	Run: func(cmd *cobra.Command, args []string) {
-		runRunList()
+		runRunList(tagFilter(cmd))
 	},
 }
 
-func runRunList() {
+func runRunList(filter bob.TagFilter) {
 	b, err := bob.Bob()
 	boblog.Log.Error(err, "Unable to initialize bob")
 
-	tasks, err := b.GetRunTasks()
+	tasks, err := b.GetRunTasks(filter)
 	boblog.Log.Error(err, "Unable to list tasks")
 
 	for _, t := range tasks {
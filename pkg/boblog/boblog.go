@@ -100,4 +100,8 @@ func (l log) UserError(err error) {
 	}
 
 	fmt.Println(aurora.Red(msg))
+
+	if uerr != nil && uerr.Summary() != "" {
+		fmt.Println(uerr.Summary())
+	}
 }
@@ -0,0 +1,58 @@
+package buildhistory
+
+import (
+	"fmt"
+	"time"
+)
+
+var ErrRunDoesNotExist = fmt.Errorf("run does not exist")
+
+// Run is a single `bob build` stored in the history.
+type Run struct {
+	// ID identifies a run, ids are increasing.
+	ID int `json:"id"`
+
+	// Project is the directory of the project the build was started in.
+	Project string `json:"project,omitempty"`
+
+	// Tasks the build was started with.
+	Tasks []string `json:"tasks"`
+
+	Start    time.Time     `json:"start"`
+	Duration time.Duration `json:"duration"`
+
+	// Error of the build, empty on success.
+	Error string `json:"error,omitempty"`
+
+	// TaskRecords holds the outcome of each task of the build.
+	TaskRecords []Task `json:"task_records"`
+}
+
+// Task is the outcome of a single task in a run.
+type Task struct {
+	Name         string        `json:"name"`
+	State        string        `json:"state"`
+	RebuildCause string        `json:"rebuild_cause,omitempty"`
+	Duration     time.Duration `json:"duration"`
+	InputHash    string        `json:"input_hash,omitempty"`
+
+	// ArtifactSource is set when the targets were loaded
+	// from the `local` or `remote` artifact store.
+	ArtifactSource string `json:"artifact_source,omitempty"`
+}
+
+// Cached returns the number of tasks which did not need a rebuild
+// or were loaded from an artifact store.
+func (r *Run) Cached() (cached int) {
+	for _, t := range r.TaskRecords {
+		if t.State == "cached" {
+			cached++
+		}
+	}
+	return cached
+}
+
+// Failed is true when the build did not succeed.
+func (r *Run) Failed() bool {
+	return r.Error != ""
+}
@@ -0,0 +1,150 @@
+package buildhistory
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/benchkram/errz"
+	"github.com/gofrs/flock"
+)
+
+// maxRuns is the number of runs kept in the history,
+// older runs are dropped when a run is appended.
+var maxRuns = 1000
+
+type Store interface {
+	// Append a run to the history,
+	// the id of the run is set by the store.
+	Append(run *Run) error
+
+	// Runs returns all runs, oldest first.
+	Runs() ([]*Run, error)
+
+	// Run returns the run with the given id.
+	Run(id int) (*Run, error)
+}
+
+// s stores the history as json lines in a single file.
+type s struct {
+	path string
+}
+
+// New creates a history store writing to the file at path.
+// The caller is responsible to pass a path in an existing directory.
+func New(path string) Store {
+	return &s{path: path}
+}
+
+func (s *s) Append(run *Run) (err error) {
+	defer errz.Recover(&err)
+
+	// the history is shared by all builds on the machine.
+	lock := flock.New(s.path + ".lock")
+	err = lock.Lock()
+	if err != nil {
+		return fmt.Errorf("failed to lock build history: %w", err)
+	}
+	defer func() {
+		_ = lock.Unlock()
+	}()
+
+	runs, err := s.Runs()
+	errz.Fatal(err)
+
+	run.ID = 1
+	if len(runs) > 0 {
+		run.ID = runs[len(runs)-1].ID + 1
+	}
+
+	if len(runs) >= maxRuns {
+		return s.rewrite(append(runs[len(runs)-maxRuns+1:], run))
+	}
+
+	b, err := json.Marshal(run)
+	errz.Fatal(err)
+
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0664)
+	errz.Fatal(err)
+
+	_, err = f.Write(append(b, '\n'))
+	if err != nil {
+		_ = f.Close()
+		errz.Fatal(err)
+	}
+
+	return f.Close()
+}
+
+// rewrite replaces the history with the given runs.
+// Must be called while holding the lock.
+func (s *s) rewrite(runs []*Run) (err error) {
+	defer errz.Recover(&err)
+
+	buf := bytes.NewBuffer([]byte{})
+	for _, run := range runs {
+		b, err := json.Marshal(run)
+		errz.Fatal(err)
+		buf.Write(append(b, '\n'))
+	}
+
+	// write to a temporary file first, readers never
+	// see a partially written history.
+	tmp := fmt.Sprintf("%s.%d.tmp", s.path, os.Getpid())
+	err = os.WriteFile(tmp, buf.Bytes(), 0664)
+	errz.Fatal(err)
+
+	return os.Rename(tmp, s.path)
+}
+
+func (s *s) Runs() (_ []*Run, err error) {
+	defer errz.Recover(&err)
+
+	runs := []*Run{}
+
+	f, err := os.Open(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return runs, nil
+		}
+		errz.Fatal(err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		run := &Run{}
+		err = json.Unmarshal(scanner.Bytes(), run)
+		if err != nil {
+			// skip lines which were not written completely
+			continue
+		}
+		runs = append(runs, run)
+	}
+	errz.Fatal(scanner.Err())
+
+	return runs, nil
+}
+
+func (s *s) Run(id int) (_ *Run, err error) {
+	defer errz.Recover(&err)
+
+	runs, err := s.Runs()
+	errz.Fatal(err)
+
+	for _, run := range runs {
+		if run.ID == id {
+			return run, nil
+		}
+	}
+
+	return nil, ErrRunDoesNotExist
+}
@@ -0,0 +1,85 @@
+package buildhistory
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore(t *testing.T) {
+	store := New(filepath.Join(t.TempDir(), "history"))
+
+	runs, err := store.Runs()
+	assert.Nil(t, err)
+	assert.Empty(t, runs)
+
+	first := &Run{
+		Tasks:    []string{"build"},
+		Start:    time.Now(),
+		Duration: time.Second,
+		TaskRecords: []Task{
+			{Name: "build", State: "done", RebuildCause: "input-not-in-build-info"},
+			{Name: "lib", State: "cached", ArtifactSource: "local"},
+		},
+	}
+	assert.Nil(t, store.Append(first))
+	assert.Equal(t, 1, first.ID)
+
+	second := &Run{Tasks: []string{"build"}, Error: "task failed"}
+	assert.Nil(t, store.Append(second))
+	assert.Equal(t, 2, second.ID)
+
+	runs, err = store.Runs()
+	assert.Nil(t, err)
+	assert.Len(t, runs, 2)
+
+	run, err := store.Run(1)
+	assert.Nil(t, err)
+	assert.Equal(t, first.TaskRecords, run.TaskRecords)
+	assert.Equal(t, 1, run.Cached())
+	assert.False(t, run.Failed())
+
+	_, err = store.Run(3)
+	assert.ErrorIs(t, err, ErrRunDoesNotExist)
+}
+
+func TestStoreConcurrentAppend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// a store for each build
+			assert.Nil(t, New(path).Append(&Run{Tasks: []string{"build"}}))
+		}()
+	}
+	wg.Wait()
+
+	runs, err := New(path).Runs()
+	assert.Nil(t, err)
+	assert.Len(t, runs, 10)
+	for i, run := range runs {
+		assert.Equal(t, i+1, run.ID)
+	}
+}
+
+func TestStoreMaxRuns(t *testing.T) {
+	defer func(max int) { maxRuns = max }(maxRuns)
+	maxRuns = 3
+
+	store := New(filepath.Join(t.TempDir(), "history"))
+	for i := 0; i < 5; i++ {
+		assert.Nil(t, store.Append(&Run{Tasks: []string{"build"}}))
+	}
+
+	runs, err := store.Runs()
+	assert.Nil(t, err)
+	assert.Len(t, runs, 3)
+	assert.Equal(t, 3, runs[0].ID)
+	assert.Equal(t, 5, runs[2].ID)
+}
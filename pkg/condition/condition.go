@@ -0,0 +1,320 @@
+// Package condition evaluates the `if:` expressions of tasks.
+//
+// An expression compares values with `==` and `!=` and combines
+// the results with `&&`, `||`, `!` and parentheses, e.g.
+//
+//	os == "linux" && (arch == "amd64" || env.CI == "true")
+//	vars.variant != "minimal" && !exists("vendor/")
+//
+// Available values are
+//
+//	os, arch        the operating system and architecture bob runs on
+//	vars.NAME       a variable of the bobfile, undefined variables are an error
+//	env.NAME        an environment variable, empty when unset
+//	exists("path")  true if the path relative to the task's directory exists
+//	true, false     boolean literals
+//	"text", 'text'  string literals
+//
+// Booleans are the strings "true" and "false". A value on its own is
+// true when it's neither empty nor "false", e.g. `env.CI`.
+//
+// `!` binds weaker than a comparison, `!a == b` is parsed as `!(a == b)`.
+package condition
+
+import (
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrInvalidCondition  = fmt.Errorf("invalid condition")
+	ErrUndefinedVariable = fmt.Errorf("undefined variable")
+)
+
+// Context provides the values an expression is evaluated against.
+type Context struct {
+	OS   string
+	Arch string
+
+	Vars map[string]string
+	Env  map[string]string
+
+	// Exists reports if a path exists.
+	Exists func(path string) bool
+}
+
+// Condition is a parsed expression.
+type Condition struct {
+	expr string
+	root node
+}
+
+// Parse parses an expression.
+func Parse(expr string) (*Condition, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %s", ErrInvalidCondition, expr, err.Error())
+	}
+
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err == nil && !p.done() {
+		err = fmt.Errorf("unexpected %q", p.peek().text)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %s", ErrInvalidCondition, expr, err.Error())
+	}
+
+	return &Condition{expr: expr, root: root}, nil
+}
+
+// Eval evaluates the condition.
+func (c *Condition) Eval(ctx Context) (bool, error) {
+	v, err := c.root.eval(&ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to evaluate condition %q: %w", c.expr, err)
+	}
+	return truthy(v), nil
+}
+
+func (c *Condition) String() string {
+	return c.expr
+}
+
+func truthy(v string) bool {
+	return v != "" && v != "false"
+}
+
+func boolean(b bool) string {
+	if b {
+		return "true"
+	}
+	return "false"
+}
+
+type node interface {
+	eval(ctx *Context) (string, error)
+}
+
+type literal string
+
+func (l literal) eval(_ *Context) (string, error) {
+	return string(l), nil
+}
+
+// ident is one of os, arch, vars.NAME or env.NAME.
+type ident string
+
+func (i ident) eval(ctx *Context) (string, error) {
+	name := string(i)
+	switch {
+	case name == "os":
+		return ctx.OS, nil
+	case name == "arch":
+		return ctx.Arch, nil
+	case strings.HasPrefix(name, "vars."):
+		key := strings.TrimPrefix(name, "vars.")
+		v, ok := ctx.Vars[key]
+		if !ok {
+			return "", fmt.Errorf("%w %q", ErrUndefinedVariable, key)
+		}
+		return v, nil
+	case strings.HasPrefix(name, "env."):
+		return ctx.Env[strings.TrimPrefix(name, "env.")], nil
+	default:
+		return "", fmt.Errorf("unknown identifier %q", name)
+	}
+}
+
+type exists struct {
+	path string
+}
+
+func (e exists) eval(ctx *Context) (string, error) {
+	if ctx.Exists == nil {
+		return boolean(false), nil
+	}
+	return boolean(ctx.Exists(e.path)), nil
+}
+
+type not struct {
+	n node
+}
+
+func (n not) eval(ctx *Context) (string, error) {
+	v, err := n.n.eval(ctx)
+	if err != nil {
+		return "", err
+	}
+	return boolean(!truthy(v)), nil
+}
+
+type binary struct {
+	op          string
+	left, right node
+}
+
+func (b binary) eval(ctx *Context) (string, error) {
+	l, err := b.left.eval(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	// short circuit
+	switch {
+	case b.op == "&&" && !truthy(l):
+		return boolean(false), nil
+	case b.op == "||" && truthy(l):
+		return boolean(true), nil
+	}
+
+	r, err := b.right.eval(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	switch b.op {
+	case "==":
+		return boolean(l == r), nil
+	case "!=":
+		return boolean(l != r), nil
+	default:
+		return boolean(truthy(r)), nil
+	}
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *parser) peek() token {
+	if p.done() {
+		return token{kind: tokenEnd, text: "end of expression"}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *parser) expect(kind tokenKind, text string) error {
+	t := p.next()
+	if t.kind != kind || t.text != text {
+		return fmt.Errorf("expected %q, got %q", text, t.text)
+	}
+	return nil
+}
+
+// parseOr parses `and ( "||" and )*`.
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for t := p.peek(); t.kind == tokenOp && t.text == "||"; t = p.peek() {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = binary{op: "||", left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseAnd parses `unary ( "&&" unary )*`.
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for t := p.peek(); t.kind == tokenOp && t.text == "&&"; t = p.peek() {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = binary{op: "&&", left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseUnary parses `"!" unary | comparison`.
+// A negation applies to a whole comparison, `!a == b` is `!(a == b)`.
+func (p *parser) parseUnary() (node, error) {
+	if t := p.peek(); t.kind == tokenOp && t.text == "!" {
+		p.next()
+		n, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return not{n: n}, nil
+	}
+	return p.parseComparison()
+}
+
+// parseComparison parses `operand ( ( "==" | "!=" ) operand )?`.
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind == tokenOp && (t.text == "==" || t.text == "!=") {
+		p.next()
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return binary{op: t.text, left: left, right: right}, nil
+	}
+	return left, nil
+}
+
+// parseOperand parses `"(" or ")" | string | ident | exists "(" string ")"`.
+func (p *parser) parseOperand() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenString:
+		return literal(t.text), nil
+	case tokenOp:
+		if t.text != "(" {
+			break
+		}
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return n, p.expect(tokenOp, ")")
+	case tokenIdent:
+		switch {
+		case t.text == "true" || t.text == "false":
+			return literal(t.text), nil
+		case t.text == "exists":
+			err := p.expect(tokenOp, "(")
+			if err != nil {
+				return nil, err
+			}
+			path := p.next()
+			if path.kind != tokenString {
+				return nil, fmt.Errorf("exists expects a string, got %q", path.text)
+			}
+			return exists{path: path.text}, p.expect(tokenOp, ")")
+		case t.text == "os" || t.text == "arch":
+			return ident(t.text), nil
+		case strings.HasPrefix(t.text, "vars.") && len(t.text) > len("vars."),
+			strings.HasPrefix(t.text, "env.") && len(t.text) > len("env."):
+			return ident(t.text), nil
+		default:
+			return nil, fmt.Errorf("unknown identifier %q", t.text)
+		}
+	}
+	return nil, fmt.Errorf("unexpected %q", t.text)
+}
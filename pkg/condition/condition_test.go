@@ -0,0 +1,84 @@
+package condition
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEval(t *testing.T) {
+	ctx := Context{
+		OS:   "linux",
+		Arch: "arm64",
+		Vars: map[string]string{"variant": "full", "empty": ""},
+		Env:  map[string]string{"CI": "true", "DEBUG": "false"},
+		Exists: func(path string) bool {
+			return path == "vendor/"
+		},
+	}
+
+	tests := []struct {
+		expr     string
+		expected bool
+	}{
+		{`os == "linux"`, true},
+		{`os == 'darwin'`, false},
+		{`os != "darwin"`, true},
+		{`arch == "amd64" || arch == "arm64"`, true},
+		{`os == "linux" && arch == "amd64"`, false},
+		{`vars.variant == "full"`, true},
+		{`vars.empty`, false},
+		{`env.CI`, true},
+		{`env.DEBUG`, false},
+		{`env.UNSET`, false},
+		{`!env.UNSET`, true},
+		{`env.UNSET == ""`, true},
+		{`exists("vendor/")`, true},
+		{`!exists("node_modules")`, true},
+		{`true`, true},
+		{`false || !(os == "linux" && env.CI == "true")`, false},
+		{`exists("vendor/") == true`, true},
+		{`"say \"hi\"" == 'say "hi"'`, true},
+		// && binds stronger than ||
+		{`os == "linux" || os == "darwin" && arch == "amd64"`, true},
+		// ! applies to the whole comparison
+		{`!os == "true"`, true},
+		// short circuit skips the undefined variable
+		{`os == "darwin" && vars.undefined == ""`, false},
+	}
+
+	for _, test := range tests {
+		c, err := Parse(test.expr)
+		assert.Nil(t, err, test.expr)
+
+		result, err := c.Eval(ctx)
+		assert.Nil(t, err, test.expr)
+		assert.Equal(t, test.expected, result, test.expr)
+	}
+}
+
+func TestEvalUndefinedVariable(t *testing.T) {
+	c, err := Parse(`vars.undefined == ""`)
+	assert.Nil(t, err)
+
+	_, err = c.Eval(Context{})
+	assert.ErrorIs(t, err, ErrUndefinedVariable)
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, expr := range []string{
+		``,
+		`os ==`,
+		`os = "linux"`,
+		`(os == "linux"`,
+		`os == "linux")`,
+		`platform == "linux"`,
+		`vars.`,
+		`exists(path)`,
+		`"unterminated`,
+		`os == "linux" "darwin"`,
+	} {
+		_, err := Parse(expr)
+		assert.ErrorIs(t, err, ErrInvalidCondition, expr)
+	}
+}
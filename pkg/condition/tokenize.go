@@ -0,0 +1,93 @@
+package condition
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokenEnd tokenKind = iota
+	tokenOp
+	tokenString
+	tokenIdent
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// tokenize splits an expression into operators, string literals and identifiers.
+func tokenize(expr string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(' || c == ')':
+			tokens = append(tokens, token{kind: tokenOp, text: string(c)})
+			i++
+		case strings.HasPrefix(expr[i:], "==") || strings.HasPrefix(expr[i:], "!=") ||
+			strings.HasPrefix(expr[i:], "&&") || strings.HasPrefix(expr[i:], "||"):
+			tokens = append(tokens, token{kind: tokenOp, text: expr[i : i+2]})
+			i += 2
+		case c == '!':
+			tokens = append(tokens, token{kind: tokenOp, text: "!"})
+			i++
+		case c == '"' || c == '\'':
+			s, n, err := readString(expr[i:])
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, text: s})
+			i += n
+		case isIdentChar(c):
+			start := i
+			for i < len(expr) && (isIdentChar(expr[i]) || expr[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: expr[start:i]})
+		default:
+			return nil, fmt.Errorf("unexpected character %q", c)
+		}
+	}
+
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty expression")
+	}
+	return tokens, nil
+}
+
+// readString reads a string literal quoted by its first character.
+// A backslash escapes the next character.
+// Returns the unquoted string and the number of bytes read.
+func readString(s string) (string, int, error) {
+	quote := s[0]
+
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i+1 < len(s) {
+				i++
+				b.WriteByte(s[i])
+			}
+		case quote:
+			return b.String(), i + 1, nil
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+
+	return "", 0, fmt.Errorf("unterminated string %s", s)
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || c == '-' ||
+		(c >= 'a' && c <= 'z') ||
+		(c >= 'A' && c <= 'Z') ||
+		(c >= '0' && c <= '9')
+}
@@ -1,6 +1,10 @@
 package envutil
 
-import "strings"
+import (
+	"os"
+	"sort"
+	"strings"
+)
 
 // Merge two lists of environment variables in the "key=value" format.
 // If variables are duplicated, the one from `b` is kept.
@@ -28,3 +32,44 @@ func Merge(a []string, b []string) []string {
 
 	return newEnv
 }
+
+// FromMap converts a map of variables to the "key=value" format,
+// sorted by key.
+func FromMap(m map[string]string) []string {
+	env := make([]string, 0, len(m))
+	for key, value := range m {
+		env = append(env, key+"="+value)
+	}
+	sort.Strings(env)
+	return env
+}
+
+// Lookup returns the variables of the host environment with
+// the given keys in the "key=value" format. Unset variables are omitted.
+func Lookup(keys []string) []string {
+	var env []string
+	for _, key := range keys {
+		if value, ok := os.LookupEnv(key); ok {
+			env = append(env, key+"="+value)
+		}
+	}
+	return env
+}
+
+// Without returns the variables of `a` whose keys are not in `b`.
+func Without(a []string, b []string) []string {
+	keys := make(map[string]bool, len(b))
+	for _, v := range b {
+		pair := strings.SplitN(v, "=", 2)
+		keys[pair[0]] = true
+	}
+
+	var env []string
+	for _, v := range a {
+		pair := strings.SplitN(v, "=", 2)
+		if !keys[pair[0]] {
+			env = append(env, v)
+		}
+	}
+	return env
+}
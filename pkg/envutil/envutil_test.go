@@ -40,3 +40,23 @@ func TestMergeEnv(t *testing.T) {
 		assert.Equal(t, tc.expectedResult, Merge(tc.first, tc.second))
 	}
 }
+
+func TestFromMap(t *testing.T) {
+	assert.Equal(t, []string{"A=1", "B=x=y"}, FromMap(map[string]string{"B": "x=y", "A": "1"}))
+	assert.Equal(t, []string{}, FromMap(nil))
+}
+
+func TestLookup(t *testing.T) {
+	t.Setenv("BOB_TEST_LOOKUP", "value")
+	t.Setenv("BOB_TEST_LOOKUP_EMPTY", "")
+
+	assert.Equal(t,
+		[]string{"BOB_TEST_LOOKUP=value", "BOB_TEST_LOOKUP_EMPTY="},
+		Lookup([]string{"BOB_TEST_LOOKUP", "BOB_TEST_LOOKUP_UNSET", "BOB_TEST_LOOKUP_EMPTY"}),
+	)
+}
+
+func TestWithout(t *testing.T) {
+	assert.Equal(t, []string{"A=1", "C"}, Without([]string{"A=1", "B=2", "C"}, []string{"B=other", "D=4"}))
+	assert.Nil(t, Without([]string{"A=1"}, []string{"A"}))
+}
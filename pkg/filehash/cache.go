@@ -0,0 +1,247 @@
+package filehash
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// racyWindow is the time span in which a file could have been modified
+// again without changing its timestamps, depending on the timestamp
+// granularity of the filesystem. Files modified within this window are
+// always rehashed.
+const racyWindow = 2 * time.Second
+
+// maxUnused is the time after which entries of files
+// which have not been hashed are dropped from the cache.
+const maxUnused = 30 * 24 * time.Hour
+
+// timeNow allows tests to control the current time.
+var timeNow = time.Now
+
+// Cache stores the hash of files keyed by their stat information.
+// Files are only read when their size, mtime, ctime or inode changed.
+//
+// A cache can be used concurrently and is safe to be shared
+// by multiple processes through the file it is persisted to.
+type Cache struct {
+	// path of the file the cache is persisted to.
+	path string
+
+	mu      sync.Mutex
+	loaded  bool
+	entries map[string]cacheEntry
+	// updated entries which need to be persisted.
+	updated map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	Size  int64  `json:"size"`
+	Mtime int64  `json:"mtime"`
+	Ctime int64  `json:"ctime"`
+	Inode uint64 `json:"inode"`
+	Hash  []byte `json:"hash"`
+
+	// LastUsed is the unix time the entry was last used.
+	LastUsed int64 `json:"last_used"`
+}
+
+// NewCache creates a cache persisted to the file at path,
+// the file is created on the first call to Save.
+func NewCache(path string) *Cache {
+	return &Cache{
+		path:    path,
+		entries: make(map[string]cacheEntry),
+		updated: make(map[string]cacheEntry),
+	}
+}
+
+// Hash returns the hash of a file's content. The hash is read from
+// the cache when the file is unchanged, otherwise the file is hashed.
+func (c *Cache) Hash(file string) ([]byte, error) {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(abs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open: %w", err)
+	}
+	inode, ctime := fileStat(info)
+	current := cacheEntry{
+		Size:  info.Size(),
+		Mtime: info.ModTime().UnixNano(),
+		Ctime: ctime,
+		Inode: inode,
+	}
+
+	now := timeNow()
+
+	c.mu.Lock()
+	c.load()
+	entry, ok := c.entries[abs]
+	c.mu.Unlock()
+
+	if ok && entry.matches(current) {
+		// avoid persisting the cache on each
+		// use only to update the usage time.
+		if now.Unix()-entry.LastUsed > int64((24 * time.Hour).Seconds()) {
+			entry.LastUsed = now.Unix()
+			c.mu.Lock()
+			c.entries[abs] = entry
+			c.updated[abs] = entry
+			c.mu.Unlock()
+		}
+		return entry.Hash, nil
+	}
+
+	h, err := Hash(abs)
+	if err != nil {
+		return nil, err
+	}
+
+	if reliable(current, now) {
+		current.Hash = h
+		current.LastUsed = now.Unix()
+		c.mu.Lock()
+		c.entries[abs] = current
+		c.updated[abs] = current
+		c.mu.Unlock()
+	}
+
+	return h, nil
+}
+
+func (e cacheEntry) matches(other cacheEntry) bool {
+	return e.Size == other.Size &&
+		e.Mtime == other.Mtime &&
+		e.Ctime == other.Ctime &&
+		e.Inode == other.Inode
+}
+
+// reliable checks if the timestamps of a file can be trusted
+// to detect subsequent changes of the file.
+func reliable(e cacheEntry, now time.Time) bool {
+	if e.Mtime <= 0 {
+		return false
+	}
+
+	racy := now.Add(-racyWindow).UnixNano()
+	if e.Mtime > racy {
+		// recently modified or modification time in the future.
+		return false
+	}
+	if e.Ctime > racy {
+		return false
+	}
+
+	return true
+}
+
+// load the persisted entries, a missing or
+// unreadable cache file results in an empty cache.
+//
+// Must be called with the mutex held.
+func (c *Cache) load() {
+	if c.loaded {
+		return
+	}
+	c.loaded = true
+
+	entries, err := readCacheFile(c.path)
+	if err != nil {
+		return
+	}
+	for file, entry := range entries {
+		if _, ok := c.entries[file]; !ok {
+			c.entries[file] = entry
+		}
+	}
+}
+
+// Save persists the entries updated since the cache was loaded.
+// Entries written by other processes in the meantime are kept.
+func (c *Cache) Save() (err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.updated) == 0 {
+		return nil
+	}
+
+	err = os.MkdirAll(filepath.Dir(c.path), 0775)
+	if err != nil {
+		return err
+	}
+
+	lock := flock.New(c.path + ".lock")
+	err = lock.Lock()
+	if err != nil {
+		return fmt.Errorf("failed to lock hash cache: %w", err)
+	}
+	defer func() {
+		_ = lock.Unlock()
+	}()
+
+	entries, err := readCacheFile(c.path)
+	if err != nil {
+		// start over with a corrupted cache
+		entries = make(map[string]cacheEntry)
+	}
+	for file, entry := range c.updated {
+		entries[file] = entry
+	}
+
+	unused := timeNow().Add(-maxUnused).Unix()
+	for file, entry := range entries {
+		if entry.LastUsed < unused {
+			delete(entries, file)
+		}
+	}
+
+	b, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+
+	// write to a temporary file first, readers never
+	// see a partially written cache.
+	tmp := fmt.Sprintf("%s.%d.tmp", c.path, os.Getpid())
+	err = os.WriteFile(tmp, b, 0664)
+	if err != nil {
+		return err
+	}
+	err = os.Rename(tmp, c.path)
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+
+	c.updated = make(map[string]cacheEntry)
+	return nil
+}
+
+func readCacheFile(path string) (map[string]cacheEntry, error) {
+	entries := make(map[string]cacheEntry)
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return entries, nil
+		}
+		return nil, err
+	}
+
+	err = json.Unmarshal(b, &entries)
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
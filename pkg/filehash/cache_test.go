@@ -0,0 +1,70 @@
+package filehash
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	dir := t.TempDir()
+	cachePath := filepath.Join(dir, "cache")
+	file := filepath.Join(dir, "file")
+	assert.Nil(t, os.WriteFile(file, []byte("content"), 0664))
+
+	expected, err := Hash(file)
+	assert.Nil(t, err)
+
+	// pretend the file was modified long ago
+	timeNow = func() time.Time { return time.Now().Add(time.Hour) }
+	defer func() { timeNow = time.Now }()
+
+	c := NewCache(cachePath)
+	h, err := c.Hash(file)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, h)
+	assert.Nil(t, c.Save())
+
+	// tamper the persisted hash to detect its usage
+	b, err := os.ReadFile(cachePath)
+	assert.Nil(t, err)
+	entries := map[string]cacheEntry{}
+	assert.Nil(t, json.Unmarshal(b, &entries))
+	assert.Len(t, entries, 1)
+	entry := entries[file]
+	entry.Hash = []byte("cached")
+	entries[file] = entry
+	b, err = json.Marshal(entries)
+	assert.Nil(t, err)
+	assert.Nil(t, os.WriteFile(cachePath, b, 0664))
+
+	// unchanged files are not read
+	h, err = NewCache(cachePath).Hash(file)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("cached"), h)
+
+	// changed files are rehashed
+	assert.Nil(t, os.WriteFile(file, []byte("changed"), 0664))
+	expected, err = Hash(file)
+	assert.Nil(t, err)
+	h, err = NewCache(cachePath).Hash(file)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, h)
+}
+
+func TestCacheRacyFile(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	assert.Nil(t, os.WriteFile(file, []byte("content"), 0664))
+
+	// a file modified just now could change again
+	// without changing its timestamps.
+	c := NewCache(filepath.Join(dir, "cache"))
+	_, err := c.Hash(file)
+	assert.Nil(t, err)
+	assert.Empty(t, c.updated)
+}
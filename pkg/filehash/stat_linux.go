@@ -0,0 +1,15 @@
+package filehash
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileStat returns the inode and the ctime in nanoseconds of a file.
+func fileStat(info os.FileInfo) (inode uint64, ctime int64) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, 0
+	}
+	return st.Ino, st.Ctim.Nano()
+}
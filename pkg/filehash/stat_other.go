@@ -0,0 +1,14 @@
+//go:build !linux && !darwin
+// +build !linux,!darwin
+
+package filehash
+
+import (
+	"os"
+)
+
+// fileStat is not supported on this platform,
+// files are compared by size and mtime only.
+func fileStat(info os.FileInfo) (inode uint64, ctime int64) {
+	return 0, 0
+}
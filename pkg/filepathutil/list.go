@@ -1,12 +1,13 @@
 package filepathutil
 
 import (
+	"bufio"
 	"fmt"
-	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
 
-	"github.com/yargevad/filepathx"
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
 )
 
 // DefaultIgnores
@@ -23,98 +24,287 @@ func ClearListRecursiveCache() {
 	// listRecursiveCache = make(map[string][]string, 1024)
 }
 
-func ListRecursive(inp string) (all []string, err error) {
-	// if result, ok := listRecursiveCache[inp]; ok {
-	// 	return result, nil
-	// }
-
-	// FIXME: when "*" is passed as input it's likely to hit the cache
-	// as there is no further information. Think how to handle the cache correctly
-	// in those cases. For now the cache is disabled!
-
-	// FIXME: new list recursive
-	// * does input contain a glob? see https://pkg.go.dev/path/filepath#Match => read with filepathx.Glob
-	// * check if input is a file => add file
-	// * check if input is a dir => add files in dir recursively
-	//
-	// More input:
-	// https://github.com/iriri/minimal/blob/9b2348d09c1ab2c25505f9933a3591ef9db6522a/gitignore/gitignore.go#L245
-	// https://github.com/zabawaba99/go-gitignore/
-	// https://github.com/gobwas/glob
-	//
-	// Thoughts: Is it possible to compile a ignoreList upfront?
-	// Then check if the accessed file || dir can be skipped.
-	// Maybe it's even possible to call skipdir on a walk func.
-
-	// FIXME: possibly ignore here too, before calling listDir
-	if s, err := os.Stat(inp); err != nil || !s.IsDir() {
-		// File
-		// Use glob for unknowns (wildcard-paths) and existing files (non-dirs)
-		matches, err := filepathx.Glob(inp)
+// ListOptions controls which parts of a directory are walked by List.
+type ListOptions struct {
+	// Ignores are gitignore-style patterns relative to the listed directory.
+	// Ignored directories are not walked.
+	Ignores []string
+
+	// IgnoreFiles are the names of files containing gitignore-style
+	// patterns, e.g. `.bobignore`. They are read from every walked
+	// directory and apply to the directory and its subdirectories.
+	IgnoreFiles []string
+
+	// Skip are absolute paths of files and directories which are not walked.
+	Skip []string
+}
+
+// List lists the files inside of dir selected by gitignore-style patterns.
+//
+// Patterns are evaluated in order and the last matching pattern wins.
+// A pattern selects the matching files, a pattern prefixed with `!`
+// deselects them again. A later pattern can re-include a deselected file.
+//
+//	src/
+//	!src/**/*_test.go
+//	src/testdata/keep_test.go
+//
+// Patterns containing a slash are anchored to dir, others match at any
+// depth. A trailing slash only matches directories, `**` matches any
+// number of directories. Absolute paths inside of dir and paths prefixed
+// with `./` are converted to anchored patterns, paths pointing upwards
+// are rejected.
+//
+// Ignored directories are pruned during the walk, as are directories
+// which can't contain files selected by an anchored pattern.
+// Returns the absolute paths of the selected files.
+// Safe for concurrent use.
+func List(dir string, patterns []string, opts ListOptions) (_ []string, err error) {
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &walker{
+		ignoreFiles: opts.IgnoreFiles,
+		skip:        make(map[string]bool, len(opts.Skip)),
+	}
+
+	var walkAll bool
+	for _, p := range patterns {
+		p, err = normalizePattern(dir, p)
 		if err != nil {
-			return nil, fmt.Errorf("failed to glob %q: %w", inp, err)
+			return nil, err
+		}
+		w.selectors = append(w.selectors, gitignore.ParsePattern(p, nil))
+
+		if strings.HasPrefix(p, "!") {
+			continue
+		}
+		if root := walkRoot(p); len(root) > 0 {
+			w.roots = append(w.roots, root)
+		} else {
+			walkAll = true
 		}
+	}
+	if walkAll {
+		w.roots = nil
+	} else if len(w.roots) == 0 {
+		// nothing selected
+		return []string{}, nil
+	}
 
-		for _, m := range matches {
-			s, err := os.Stat(m)
-			if err == nil && !s.IsDir() {
+	ignores := make([]gitignore.Pattern, 0, len(opts.Ignores))
+	for _, p := range opts.Ignores {
+		ignores = append(ignores, gitignore.ParsePattern(p, nil))
+	}
 
-				// Existing file
-				all = append(all, m)
-			} else {
-				// Directory
-				files, err := listDir(m)
-				if err != nil {
-					return nil, fmt.Errorf("failed to list dir: %w", err)
-				}
+	for _, path := range opts.Skip {
+		w.skip[filepath.Clean(path)] = true
+	}
 
-				all = append(all, files...)
-			}
-		}
-	} else {
-		// Directory
-		files, err := listDir(inp)
+	err = w.walk(dir, []string{}, ignores)
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk dir %q: %w", dir, err)
+	}
+
+	return w.files, nil
+}
+
+// Match reports if the file at the absolute path is selected by the patterns
+// of List, without considering ignores. The file doesn't need to exist.
+func Match(dir string, patterns []string, path string) (_ bool, err error) {
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return false, err
+	}
+
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false, err
+	}
+	if rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return false, nil
+	}
+
+	selectors := make([]gitignore.Pattern, 0, len(patterns))
+	for _, p := range patterns {
+		p, err = normalizePattern(dir, p)
 		if err != nil {
-			return nil, fmt.Errorf("failed to list dir: %w", err)
+			return false, err
 		}
-
-		all = append(all, files...)
+		selectors = append(selectors, gitignore.ParsePattern(p, nil))
 	}
 
-	// listRecursiveMap[inp] = all
-	return all, nil
+	return gitignore.NewMatcher(selectors).Match(strings.Split(filepath.ToSlash(rel), "/"), false), nil
 }
 
-var WalkedDirs = map[string]int{}
+type walker struct {
+	// selectors are the patterns selecting files.
+	selectors []gitignore.Pattern
 
-func listDir(path string) ([]string, error) {
-	times := WalkedDirs[path]
-	WalkedDirs[path] = times + 1
+	// roots are the directories or files containing all selected
+	// files in components relative to the listed directory.
+	// All of the directory is walked when empty.
+	roots [][]string
 
-	var all []string
-	if err := filepath.WalkDir(path, func(p string, fi fs.DirEntry, err error) error {
+	ignoreFiles []string
+	skip        map[string]bool
+
+	files []string
+}
+
+// walk walks the directory at path, rel are the components of
+// path relative to the listed directory. ignores are the patterns
+// from the parent directories.
+func (w *walker) walk(path string, rel []string, ignores []gitignore.Pattern) error {
+	for _, name := range w.ignoreFiles {
+		patterns, err := readIgnoreFile(filepath.Join(path, name), rel)
 		if err != nil {
 			return err
 		}
+		// copy to not alter the ignores of sibling directories
+		ignores = append(ignores[:len(ignores):len(ignores)], patterns...)
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		p := filepath.Join(path, entry.Name())
+		r := append(rel[:len(rel):len(rel)], entry.Name())
+
+		if !w.onRoute(r) || w.skip[p] {
+			continue
+		}
 
-		// Skip default ignored
-		if fi.IsDir() && ignored(fi.Name()) {
-			return fs.SkipDir
+		// Symlinks are not followed, they are resolved
+		// by the caller like any other file.
+		isDir := entry.IsDir()
+		if isDir && DefaultIgnores[entry.Name()] {
+			continue
 		}
+		if gitignore.NewMatcher(ignores).Match(r, isDir) {
+			continue
+		}
+
+		if isDir {
+			err = w.walk(p, r, ignores)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		if gitignore.NewMatcher(w.selectors).Match(r, false) {
+			w.files = append(w.files, p)
+		}
+	}
+
+	return nil
+}
 
-		// Skip dirs
-		if !fi.IsDir() {
-			all = append(all, p)
+// onRoute reports if rel is either leading to or located inside of a root.
+func (w *walker) onRoute(rel []string) bool {
+	if len(w.roots) == 0 {
+		return true
+	}
+
+	for _, root := range w.roots {
+		n := len(root)
+		if len(rel) < n {
+			n = len(rel)
+		}
+
+		match := true
+		for i := 0; i < n; i++ {
+			if root[i] != rel[i] {
+				match = false
+				break
+			}
 		}
+		if match {
+			return true
+		}
+	}
+
+	return false
+}
 
+// normalizePattern converts the path style patterns
+// of bob to their gitignore equivalent relative to dir.
+func normalizePattern(dir, pattern string) (string, error) {
+	var negate string
+	if strings.HasPrefix(pattern, "!") {
+		negate = "!"
+		pattern = strings.TrimPrefix(pattern, "!")
+	}
+
+	// Absolute paths inside of dir are made relative, any other
+	// leading slash anchors the pattern like in gitignore files.
+	if pattern == dir {
+		pattern = "/"
+	} else if strings.HasPrefix(pattern, dir+string(filepath.Separator)) {
+		pattern = "/" + strings.TrimPrefix(pattern, dir+string(filepath.Separator))
+	}
+	pattern = filepath.ToSlash(pattern)
+
+	if pattern == ".." || strings.HasPrefix(pattern, "../") || strings.Contains(pattern, "/../") {
+		return "", fmt.Errorf("'../' not allowed in file path %q", pattern)
+	}
+
+	switch {
+	case pattern == "." || pattern == "./" || pattern == "/":
+		pattern = "*"
+	case strings.HasPrefix(pattern, "./"):
+		pattern = "/" + strings.TrimPrefix(pattern, "./")
+	}
+
+	return negate + pattern, nil
+}
+
+// walkRoot returns the leading components of an anchored
+// pattern until the first component containing a wildcard.
+func walkRoot(pattern string) []string {
+	pattern = strings.TrimSuffix(pattern, "/")
+	if !strings.Contains(pattern, "/") {
+		// matches at any depth
 		return nil
-	}); err != nil {
-		return nil, fmt.Errorf("failed to walk dir %q: %w", path, err)
 	}
 
-	return all, nil
+	var root []string
+	for _, c := range strings.Split(strings.TrimPrefix(pattern, "/"), "/") {
+		if c == "" || strings.ContainsAny(c, `*?[\`) {
+			break
+		}
+		root = append(root, c)
+	}
+	return root
 }
 
-func ignored(fileName string) bool {
-	return DefaultIgnores[fileName]
+// readIgnoreFile reads the patterns of an ignore file
+// located in the directory with the components domain.
+// Returns no patterns in case the file does not exist.
+func readIgnoreFile(path string, domain []string) (patterns []gitignore.Pattern, _ error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.HasPrefix(line, "#") || strings.TrimSpace(line) == "" {
+			continue
+		}
+		patterns = append(patterns, gitignore.ParsePattern(line, domain))
+	}
+
+	return patterns, scanner.Err()
 }
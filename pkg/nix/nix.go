@@ -55,8 +55,10 @@ func BuildDependencies(deps []Dependency, cache *Cache) (_ DependenciesToStorePa
 	}
 
 	if len(unsatisfiedDeps) > 0 {
-		fmt.Println("Building nix dependencies...")
-		defer fmt.Println("Succeeded building nix dependencies")
+		// progress is written to stderr to keep stdout
+		// for the output of commands, e.g. `bob graph`.
+		fmt.Fprintln(os.Stderr, "Building nix dependencies...")
+		defer fmt.Fprintln(os.Stderr, "Succeeded building nix dependencies")
 	}
 
 	for _, v := range unsatisfiedDeps {
@@ -91,7 +93,7 @@ func buildPackage(pkgName string, nixpkgs string) (string, error) {
 	cmd := exec.Command("nix-build", "--no-out-link", "-E", nixExpression)
 
 	var stdoutBuf bytes.Buffer
-	cmd.Stdout = io.MultiWriter(os.Stdout, &stdoutBuf)
+	cmd.Stdout = io.MultiWriter(os.Stderr, &stdoutBuf)
 	cmd.Stderr = os.Stderr
 
 	err := cmd.Run()
@@ -115,7 +117,7 @@ func buildFile(filePath string, nixpkgs string) (string, error) {
 	cmd := exec.Command("nix-build", "--no-out-link", "-E", nixExpression)
 
 	var stdoutBuf bytes.Buffer
-	cmd.Stdout = io.MultiWriter(os.Stdout, &stdoutBuf)
+	cmd.Stdout = io.MultiWriter(os.Stderr, &stdoutBuf)
 	cmd.Stderr = os.Stderr
 
 	err := cmd.Run()
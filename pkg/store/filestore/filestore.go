@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/benchkram/bob/pkg/file"
 	"github.com/benchkram/bob/pkg/store"
@@ -33,10 +34,49 @@ func New(dir string, opts ...Option) store.Store {
 	return s
 }
 
+// tmpSuffix marks artifacts which are still written.
+const tmpSuffix = ".tmp"
+
 // NewArtifact creates a new file. The caller is responsible to call Close().
 // Existing artifacts are overwritten.
+//
+// The artifact is written to a temporary file which is moved in place on
+// Close(), an interrupted write never leaves an incomplete artifact behind.
 func (s *s) NewArtifact(_ context.Context, artifactID string, _ int64) (io.WriteCloser, error) {
-	return os.Create(filepath.Join(s.dir, artifactID))
+	f, err := os.CreateTemp(s.dir, artifactID+".*"+tmpSuffix)
+	if err != nil {
+		return nil, err
+	}
+	// temporary files are only readable by their owner
+	err = f.Chmod(0664)
+	if err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return nil, err
+	}
+	return &artifactWriter{File: f, path: filepath.Join(s.dir, artifactID)}, nil
+}
+
+// artifactWriter moves the temporary file
+// to the artifact's path when closed.
+type artifactWriter struct {
+	*os.File
+	path string
+}
+
+func (w *artifactWriter) Close() error {
+	err := w.File.Close()
+	if err != nil {
+		_ = os.Remove(w.File.Name())
+		return err
+	}
+	return os.Rename(w.File.Name(), w.path)
+}
+
+// Abort discards the temporary file.
+func (w *artifactWriter) Abort() error {
+	_ = w.File.Close()
+	return os.Remove(w.File.Name())
 }
 
 // GetArtifact opens a file
@@ -82,6 +122,9 @@ func (s *s) List(_ context.Context) (items []string, err error) {
 
 	items = []string{}
 	for _, e := range entrys {
+		if strings.HasSuffix(e.Name(), tmpSuffix) {
+			continue
+		}
 		items = append(items, e.Name())
 	}
 
@@ -0,0 +1,59 @@
+package filestore
+
+import (
+	"context"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/benchkram/bob/pkg/store"
+)
+
+func TestNewArtifact(t *testing.T) {
+	ctx := context.Background()
+	s := New(t.TempDir())
+
+	w, err := s.NewArtifact(ctx, "aaa", 0)
+	assert.Nil(t, err)
+	_, err = w.Write([]byte("artifact"))
+	assert.Nil(t, err)
+
+	// an artifact being written is not visible
+	assert.False(t, s.ArtifactExists(ctx, "aaa"))
+	items, err := s.List(ctx)
+	assert.Nil(t, err)
+	assert.Empty(t, items)
+
+	assert.Nil(t, w.Close())
+
+	assert.True(t, s.ArtifactExists(ctx, "aaa"))
+	items, err = s.List(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"aaa"}, items)
+
+	r, _, err := s.GetArtifact(ctx, "aaa")
+	assert.Nil(t, err)
+	defer r.Close()
+	b, err := io.ReadAll(r)
+	assert.Nil(t, err)
+	assert.Equal(t, "artifact", string(b))
+}
+
+func TestAbortArtifact(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	s := New(dir)
+
+	w, err := s.NewArtifact(ctx, "aaa", 0)
+	assert.Nil(t, err)
+	_, err = w.Write([]byte("incomplete"))
+	assert.Nil(t, err)
+	assert.Nil(t, store.Abort(w))
+
+	assert.False(t, s.ArtifactExists(ctx, "aaa"))
+	entries, err := os.ReadDir(dir)
+	assert.Nil(t, err)
+	assert.Empty(t, entries, "the temporary file is removed")
+}
@@ -12,6 +12,8 @@ import (
 	storeclient "github.com/benchkram/bob/pkg/store-client"
 )
 
+var ErrUploadAborted = fmt.Errorf("upload aborted")
+
 type s struct {
 	// client to call the remote store.
 	client storeclient.I
@@ -66,7 +68,17 @@ func (s *s) NewArtifact(ctx context.Context, artifactID string, size int64) (wc
 		}
 	}()
 
-	return writer, nil
+	return &artifactWriter{PipeWriter: writer}, nil
+}
+
+// artifactWriter streams an artifact to the upload.
+type artifactWriter struct {
+	*io.PipeWriter
+}
+
+// Abort fails the upload.
+func (w *artifactWriter) Abort() error {
+	return w.CloseWithError(ErrUploadAborted)
 }
 
 // GetArtifact opens a file
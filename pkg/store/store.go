@@ -27,6 +27,21 @@ type Store interface {
 	Done() error
 }
 
+// Aborter is implemented by artifact writers which are
+// able to discard an incompletely written artifact.
+type Aborter interface {
+	Abort() error
+}
+
+// Abort discards an incompletely written artifact.
+// Writers not implementing Aborter are closed.
+func Abort(w io.WriteCloser) error {
+	if a, ok := w.(Aborter); ok {
+		return a.Abort()
+	}
+	return w.Close()
+}
+
 var (
 	ErrArtifactNotFoundinSrc = fmt.Errorf("artifact not found in src")
 	ErrArtifactAlreadyExists = fmt.Errorf("artifact already exists")
@@ -28,21 +28,36 @@ func Sync(ctx context.Context, src, dst Store, id string, ignoreAlreadyExists bo
 
 	srcReader, size, err := src.GetArtifact(ctx, id)
 	errz.Fatal(err)
+	defer srcReader.Close()
 
 	dstWriter, err := dst.NewArtifact(ctx, id, size)
 	errz.Fatal(err)
 
+	// A failed or canceled sync must not
+	// leave an incomplete artifact behind.
+	var complete bool
+	defer func() {
+		if !complete {
+			_ = Abort(dstWriter)
+		}
+	}()
+
 	tr := io.TeeReader(srcReader, dstWriter)
 	buf := make([]byte, 256)
 	for {
+		errz.Fatal(ctx.Err())
+
 		_, err := tr.Read(buf)
 		if err == io.EOF {
-			_ = dstWriter.Close()
 			break
 		}
 		errz.Fatal(err)
 	}
 
+	err = dstWriter.Close()
+	errz.Fatal(err)
+	complete = true
+
 	return src.Done()
 }
 
@@ -0,0 +1,80 @@
+package store_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/benchkram/bob/pkg/store"
+	"github.com/benchkram/bob/pkg/store/filestore"
+)
+
+// cancelingStore cancels the sync after the first read of an artifact.
+type cancelingStore struct {
+	store.Store
+	cancel context.CancelFunc
+}
+
+func (s cancelingStore) GetArtifact(ctx context.Context, id string) (io.ReadCloser, int64, error) {
+	rc, size, err := s.Store.GetArtifact(ctx, id)
+	return &cancelingReader{ReadCloser: rc, cancel: s.cancel}, size, err
+}
+
+type cancelingReader struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (r *cancelingReader) Read(p []byte) (int, error) {
+	defer r.cancel()
+	return r.ReadCloser.Read(p)
+}
+
+func TestSync(t *testing.T) {
+	ctx := context.Background()
+	src := filestore.New(t.TempDir())
+	dst := filestore.New(t.TempDir())
+
+	content := bytes.Repeat([]byte("artifact"), 1024)
+	w, err := src.NewArtifact(ctx, "aaa", 0)
+	assert.Nil(t, err)
+	_, err = w.Write(content)
+	assert.Nil(t, err)
+	assert.Nil(t, w.Close())
+
+	assert.Nil(t, store.Sync(ctx, src, dst, "aaa", false))
+	r, _, err := dst.GetArtifact(ctx, "aaa")
+	assert.Nil(t, err)
+	defer r.Close()
+	synced, err := io.ReadAll(r)
+	assert.Nil(t, err)
+	assert.Equal(t, content, synced)
+
+	assert.ErrorIs(t, store.Sync(ctx, src, dst, "aaa", false), store.ErrArtifactAlreadyExists)
+}
+
+func TestSyncCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	src := filestore.New(t.TempDir())
+	dstDir := t.TempDir()
+	dst := filestore.New(dstDir)
+
+	w, err := src.NewArtifact(ctx, "aaa", 0)
+	assert.Nil(t, err)
+	_, err = w.Write(bytes.Repeat([]byte("artifact"), 1024))
+	assert.Nil(t, err)
+	assert.Nil(t, w.Close())
+
+	err = store.Sync(ctx, cancelingStore{Store: src, cancel: cancel}, dst, "aaa", false)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	entries, err := os.ReadDir(dstDir)
+	assert.Nil(t, err)
+	assert.Empty(t, entries, "neither the artifact nor a temporary file remains")
+}
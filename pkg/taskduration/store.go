@@ -0,0 +1,106 @@
+package taskduration
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// Store keeps the execution time of the last run of each task,
+// keyed by the directory of the task's project.
+//
+// A store is safe to be shared by multiple processes
+// through the file it is persisted to.
+type Store struct {
+	// path of the file the store is persisted to.
+	path string
+}
+
+// durations of tasks by project.
+type durations map[string]map[string]time.Duration
+
+// New creates a store persisted to the file at path,
+// the file is created on the first call to Update.
+func New(path string) *Store {
+	return &Store{path: path}
+}
+
+// Durations returns the execution times of the tasks of a project.
+func (s *Store) Durations(project string) (map[string]time.Duration, error) {
+	all, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	return all[project], nil
+}
+
+// Update records the execution times of tasks of a project,
+// keeping the execution times of other tasks.
+func (s *Store) Update(project string, update map[string]time.Duration) (err error) {
+	if len(update) == 0 {
+		return nil
+	}
+
+	err = os.MkdirAll(filepath.Dir(s.path), 0775)
+	if err != nil {
+		return err
+	}
+
+	lock := flock.New(s.path + ".lock")
+	err = lock.Lock()
+	if err != nil {
+		return fmt.Errorf("failed to lock task durations: %w", err)
+	}
+	defer func() {
+		_ = lock.Unlock()
+	}()
+
+	all, err := s.read()
+	if err != nil {
+		// start over with a corrupted store
+		all = make(durations)
+	}
+	if all[project] == nil {
+		all[project] = make(map[string]time.Duration, len(update))
+	}
+	for task, d := range update {
+		all[project][task] = d
+	}
+
+	b, err := json.Marshal(all)
+	if err != nil {
+		return err
+	}
+
+	// write to a temporary file first, readers never
+	// see partially written durations.
+	tmp := fmt.Sprintf("%s.%d.tmp", s.path, os.Getpid())
+	err = os.WriteFile(tmp, b, 0664)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+func (s *Store) read() (durations, error) {
+	all := make(durations)
+
+	b, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return all, nil
+		}
+		return nil, err
+	}
+
+	err = json.Unmarshal(b, &all)
+	if err != nil {
+		return nil, err
+	}
+	return all, nil
+}
@@ -0,0 +1,56 @@
+package taskduration
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "durations")
+	store := New(path)
+
+	durations, err := store.Durations("/project")
+	assert.Nil(t, err)
+	assert.Empty(t, durations)
+
+	assert.Nil(t, store.Update("/project", map[string]time.Duration{"build": time.Second, "test": time.Minute}))
+	assert.Nil(t, store.Update("/other", map[string]time.Duration{"build": time.Hour}))
+	assert.Nil(t, store.Update("/project", map[string]time.Duration{"build": 2 * time.Second}))
+
+	durations, err = New(path).Durations("/project")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]time.Duration{"build": 2 * time.Second, "test": time.Minute}, durations)
+
+	// a corrupted store is replaced
+	assert.Nil(t, os.WriteFile(path, []byte("{"), 0664))
+	_, err = store.Durations("/project")
+	assert.NotNil(t, err)
+	assert.Nil(t, store.Update("/project", map[string]time.Duration{"build": time.Second}))
+	durations, err = store.Durations("/project")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]time.Duration{"build": time.Second}, durations)
+}
+
+func TestStoreConcurrentUpdate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "durations")
+
+	tasks := []string{"a", "b", "c", "d", "e"}
+	var wg sync.WaitGroup
+	for _, task := range tasks {
+		wg.Add(1)
+		go func(task string) {
+			defer wg.Done()
+			assert.Nil(t, New(path).Update("/project", map[string]time.Duration{task: time.Second}))
+		}(task)
+	}
+	wg.Wait()
+
+	durations, err := New(path).Durations("/project")
+	assert.Nil(t, err)
+	assert.Len(t, durations, len(tasks))
+}
@@ -0,0 +1,232 @@
+package taskgraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Kind of a task.
+type Kind string
+
+const (
+	KindBuild Kind = "build"
+	KindRun   Kind = "run"
+)
+
+// State of a node describes what a build would do with the task.
+type State string
+
+const (
+	StateCached   State = "cached"
+	StateArtifact State = "load-artifact"
+	StateRebuild  State = "rebuild"
+	StateSkipped  State = "skipped"
+)
+
+// Graph is the dependency graph of tasks.
+type Graph struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+}
+
+// Node is a single task.
+type Node struct {
+	Name string `json:"name"`
+	Kind Kind   `json:"kind"`
+
+	// Inputs are the input patterns as declared in the bobfile.
+	Inputs []string `json:"inputs,omitempty"`
+
+	// Targets are the filesystem targets and docker images.
+	Targets []string `json:"targets,omitempty"`
+
+	// Dependencies are the nix dependencies.
+	Dependencies []string `json:"dependencies,omitempty"`
+
+	// State and RebuildCause are only set when
+	// the rebuild state was requested.
+	State        State  `json:"state,omitempty"`
+	RebuildCause string `json:"rebuildCause,omitempty"`
+}
+
+// Edge points from a task to a task it depends on.
+type Edge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Sort orders nodes by name and edges by source and destination.
+func (g *Graph) Sort() {
+	sort.Slice(g.Nodes, func(i, j int) bool {
+		return g.Nodes[i].Name < g.Nodes[j].Name
+	})
+	sort.Slice(g.Edges, func(i, j int) bool {
+		if g.Edges[i].From != g.Edges[j].From {
+			return g.Edges[i].From < g.Edges[j].From
+		}
+		return g.Edges[i].To < g.Edges[j].To
+	})
+}
+
+// Format of a written graph.
+type Format string
+
+const (
+	FormatDot     Format = "dot"
+	FormatMermaid Format = "mermaid"
+	FormatJSON    Format = "json"
+)
+
+var ErrInvalidFormat = fmt.Errorf("invalid format")
+
+// ParseFormat returns the format named s.
+func ParseFormat(s string) (Format, error) {
+	switch format := Format(s); format {
+	case FormatDot, FormatMermaid, FormatJSON:
+		return format, nil
+	default:
+		return "", fmt.Errorf("%w %q, supported: %s, %s, %s", ErrInvalidFormat, s, FormatDot, FormatMermaid, FormatJSON)
+	}
+}
+
+// Write the graph to w in the given format.
+func (g *Graph) Write(w io.Writer, format Format) error {
+	switch format {
+	case FormatDot:
+		return g.WriteDot(w)
+	case FormatMermaid:
+		return g.WriteMermaid(w)
+	case FormatJSON:
+		return g.WriteJSON(w)
+	default:
+		_, err := ParseFormat(string(format))
+		return err
+	}
+}
+
+// WriteJSON writes the graph as indented json.
+func (g *Graph) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(g)
+}
+
+// WriteDot writes the graph in the graphviz dot language.
+func (g *Graph) WriteDot(w io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString("digraph bob {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box];\n")
+	for _, n := range g.Nodes {
+		attrs := []string{fmt.Sprintf("label=%s", dotQuote(strings.Join(n.label(), "\n")))}
+		if n.Kind == KindRun {
+			attrs = append(attrs, "shape=ellipse")
+		}
+		if color := n.State.color(); color != "" {
+			attrs = append(attrs, "style=filled", fmt.Sprintf("fillcolor=%s", dotQuote(color)))
+		}
+		fmt.Fprintf(&b, "  %s [%s];\n", dotQuote(n.Name), strings.Join(attrs, ", "))
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  %s -> %s;\n", dotQuote(e.From), dotQuote(e.To))
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteMermaid writes the graph as mermaid flowchart.
+func (g *Graph) WriteMermaid(w io.Writer) error {
+	var b strings.Builder
+
+	// task names can contain characters not allowed in mermaid ids.
+	ids := make(map[string]string, len(g.Nodes))
+	for i, n := range g.Nodes {
+		ids[n.Name] = fmt.Sprintf("n%d", i)
+	}
+
+	b.WriteString("flowchart LR\n")
+	for _, n := range g.Nodes {
+		label := mermaidQuote(strings.Join(n.label(), "<br/>"))
+		if n.Kind == KindRun {
+			fmt.Fprintf(&b, "  %s([%s])\n", ids[n.Name], label)
+		} else {
+			fmt.Fprintf(&b, "  %s[%s]\n", ids[n.Name], label)
+		}
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  %s --> %s\n", ids[e.From], ids[e.To])
+	}
+
+	var classes []string
+	for _, n := range g.Nodes {
+		if n.State != "" {
+			classes = append(classes, fmt.Sprintf("  class %s %s\n", ids[n.Name], n.State.class()))
+		}
+	}
+	if len(classes) > 0 {
+		for _, s := range []State{StateCached, StateArtifact, StateRebuild, StateSkipped} {
+			fmt.Fprintf(&b, "  classDef %s fill:%s\n", s.class(), s.color())
+		}
+		for _, c := range classes {
+			b.WriteString(c)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// label returns the lines describing a node.
+func (n *Node) label() []string {
+	lines := []string{n.Name}
+	if len(n.Inputs) > 0 {
+		lines = append(lines, "inputs: "+strings.Join(n.Inputs, ", "))
+	}
+	if len(n.Targets) > 0 {
+		lines = append(lines, "targets: "+strings.Join(n.Targets, ", "))
+	}
+	if len(n.Dependencies) > 0 {
+		lines = append(lines, "dependencies: "+strings.Join(n.Dependencies, ", "))
+	}
+	if n.RebuildCause != "" {
+		lines = append(lines, "cause: "+n.RebuildCause)
+	}
+	return lines
+}
+
+func (s State) color() string {
+	switch s {
+	case StateCached:
+		return "#b7eb8f"
+	case StateArtifact:
+		return "#91d5ff"
+	case StateRebuild:
+		return "#ffc069"
+	case StateSkipped:
+		return "#d9d9d9"
+	default:
+		return ""
+	}
+}
+
+// class returns a mermaid class name, which must not contain dashes.
+func (s State) class() string {
+	return strings.ReplaceAll(string(s), "-", "")
+}
+
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+func mermaidQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+}
@@ -0,0 +1,87 @@
+package taskgraph
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testGraph() *Graph {
+	g := &Graph{
+		Nodes: []Node{
+			{Name: "second-level/build", Kind: KindBuild, Inputs: []string{"*.go"}, Targets: []string{"app"}, State: StateRebuild, RebuildCause: "input-not-in-build-info"},
+			{Name: "build", Kind: KindBuild, Dependencies: []string{"go_1_18"}, State: StateCached},
+			{Name: "server", Kind: KindRun},
+		},
+		Edges: []Edge{
+			{From: "server", To: "build"},
+			{From: "build", To: "second-level/build"},
+		},
+	}
+	g.Sort()
+	return g
+}
+
+func TestWriteDot(t *testing.T) {
+	var b bytes.Buffer
+	err := testGraph().Write(&b, FormatDot)
+	assert.Nil(t, err)
+
+	expected := `digraph bob {
+  rankdir=LR;
+  node [shape=box];
+  "build" [label="build\ndependencies: go_1_18", style=filled, fillcolor="#b7eb8f"];
+  "second-level/build" [label="second-level/build\ninputs: *.go\ntargets: app\ncause: input-not-in-build-info", style=filled, fillcolor="#ffc069"];
+  "server" [label="server", shape=ellipse];
+  "build" -> "second-level/build";
+  "server" -> "build";
+}
+`
+	assert.Equal(t, expected, b.String())
+}
+
+func TestWriteMermaid(t *testing.T) {
+	var b bytes.Buffer
+	err := testGraph().Write(&b, FormatMermaid)
+	assert.Nil(t, err)
+
+	expected := `flowchart LR
+  n0["build<br/>dependencies: go_1_18"]
+  n1["second-level/build<br/>inputs: *.go<br/>targets: app<br/>cause: input-not-in-build-info"]
+  n2(["server"])
+  n0 --> n1
+  n2 --> n0
+  classDef cached fill:#b7eb8f
+  classDef loadartifact fill:#91d5ff
+  classDef rebuild fill:#ffc069
+  classDef skipped fill:#d9d9d9
+  class n0 cached
+  class n1 rebuild
+`
+	assert.Equal(t, expected, b.String())
+}
+
+func TestWriteJSON(t *testing.T) {
+	var b bytes.Buffer
+	err := testGraph().Write(&b, FormatJSON)
+	assert.Nil(t, err)
+
+	var g Graph
+	err = json.Unmarshal(b.Bytes(), &g)
+	assert.Nil(t, err)
+	assert.Equal(t, testGraph(), &g)
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat("mermaid")
+	assert.Nil(t, err)
+	assert.Equal(t, FormatMermaid, format)
+
+	_, err = ParseFormat("svg")
+	assert.ErrorIs(t, err, ErrInvalidFormat)
+
+	err = testGraph().Write(&bytes.Buffer{}, Format("svg"))
+	assert.ErrorIs(t, err, ErrInvalidFormat)
+}
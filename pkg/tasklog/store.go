@@ -0,0 +1,163 @@
+package tasklog
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/benchkram/errz"
+)
+
+var ErrLogDoesNotExist = fmt.Errorf("log does not exist")
+
+const extension = ".log"
+
+// maxLogs is the number of logs kept for each task by Prune.
+const maxLogs = 10
+
+type Store interface {
+	// Create returns a writer to the log of a task
+	// run with the given input hash. An existing
+	// log of the same input hash is overwritten.
+	Create(taskname, hash string) (io.WriteCloser, error)
+
+	// Get returns the log of a task
+	// run with the given input hash.
+	Get(taskname, hash string) ([]byte, error)
+
+	// Last returns the most recently written log of a task.
+	Last(taskname string) ([]byte, error)
+
+	// Project returns a store for the logs of the project
+	// in the given directory, as task names are only unique
+	// within a project.
+	Project(dir string) Store
+
+	// Prune removes all but the most recent logs of each task.
+	Prune() error
+}
+
+// s stores each log in a separate file
+// at `<dir>/<taskname>/<hash>.log`.
+type s struct {
+	dir string
+}
+
+// New creates a log store writing to dir.
+func New(dir string) Store {
+	return &s{dir: dir}
+}
+
+func (s *s) Create(taskname, hash string) (_ io.WriteCloser, err error) {
+	defer errz.Recover(&err)
+
+	path := s.path(taskname, hash)
+	err = os.MkdirAll(filepath.Dir(path), 0775)
+	errz.Fatal(err)
+
+	return os.Create(path)
+}
+
+func (s *s) Get(taskname, hash string) (_ []byte, err error) {
+	defer errz.Recover(&err)
+
+	b, err := os.ReadFile(s.path(taskname, hash))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrLogDoesNotExist
+		}
+		errz.Fatal(err)
+	}
+
+	return b, nil
+}
+
+func (s *s) Last(taskname string) (_ []byte, err error) {
+	defer errz.Recover(&err)
+
+	dir := filepath.Join(s.dir, filepath.FromSlash(taskname))
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrLogDoesNotExist
+		}
+		errz.Fatal(err)
+	}
+
+	var last string
+	var lastInfo os.FileInfo
+	for _, entry := range entries {
+		// directories hold the logs of tasks in child bobfiles
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), extension) {
+			continue
+		}
+
+		info, err := entry.Info()
+		errz.Fatal(err)
+
+		if lastInfo == nil || info.ModTime().After(lastInfo.ModTime()) {
+			last = entry.Name()
+			lastInfo = info
+		}
+	}
+
+	if last == "" {
+		return nil, ErrLogDoesNotExist
+	}
+
+	return os.ReadFile(filepath.Join(dir, last))
+}
+
+func (s *s) Project(dir string) Store {
+	sum := sha256.Sum256([]byte(dir))
+	return New(filepath.Join(s.dir, hex.EncodeToString(sum[:8])))
+}
+
+func (s *s) Prune() (err error) {
+	defer errz.Recover(&err)
+
+	logs := make(map[string][]os.FileInfo)
+	err = filepath.Walk(s.dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if !info.IsDir() && strings.HasSuffix(info.Name(), extension) {
+			dir := filepath.Dir(path)
+			logs[dir] = append(logs[dir], info)
+		}
+		return nil
+	})
+	errz.Fatal(err)
+
+	for dir, infos := range logs {
+		if len(infos) <= maxLogs {
+			continue
+		}
+
+		// most recent first
+		sort.Slice(infos, func(i, j int) bool {
+			return infos[i].ModTime().After(infos[j].ModTime())
+		})
+		for _, info := range infos[maxLogs:] {
+			err = os.Remove(filepath.Join(dir, info.Name()))
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				errz.Fatal(err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *s) path(taskname, hash string) string {
+	return filepath.Join(s.dir, filepath.FromSlash(taskname), hash+extension)
+}
@@ -0,0 +1,108 @@
+package tasklog
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func write(t *testing.T, store Store, taskname, hash, content string) {
+	w, err := store.Create(taskname, hash)
+	assert.Nil(t, err)
+	_, err = w.Write([]byte(content))
+	assert.Nil(t, err)
+	assert.Nil(t, w.Close())
+}
+
+func TestStore(t *testing.T) {
+	dir := t.TempDir()
+	store := New(dir)
+
+	_, err := store.Get("build", "abc")
+	assert.ErrorIs(t, err, ErrLogDoesNotExist)
+	_, err = store.Last("build")
+	assert.ErrorIs(t, err, ErrLogDoesNotExist)
+
+	write(t, store, "build", "abc", "first\n")
+	write(t, store, "build", "def", "second\n")
+	write(t, store, "sub/build", "abc", "child\n")
+
+	// make the order of the logs independent of the filesystem's timestamp resolution
+	past := time.Now().Add(-time.Minute)
+	assert.Nil(t, os.Chtimes(filepath.Join(dir, "build", "abc.log"), past, past))
+
+	log, err := store.Get("build", "abc")
+	assert.Nil(t, err)
+	assert.Equal(t, "first\n", string(log))
+
+	log, err = store.Last("build")
+	assert.Nil(t, err)
+	assert.Equal(t, "second\n", string(log))
+
+	log, err = store.Last("sub/build")
+	assert.Nil(t, err)
+	assert.Equal(t, "child\n", string(log))
+
+	// a rerun overwrites the log of the same input hash
+	write(t, store, "build", "abc", "rerun\n")
+	log, err = store.Get("build", "abc")
+	assert.Nil(t, err)
+	assert.Equal(t, "rerun\n", string(log))
+}
+
+func TestStoreProject(t *testing.T) {
+	store := New(t.TempDir())
+	first := store.Project("/home/user/first")
+	second := store.Project("/home/user/second")
+
+	write(t, first, "build", "abc", "first\n")
+
+	_, err := second.Last("build")
+	assert.ErrorIs(t, err, ErrLogDoesNotExist)
+
+	log, err := store.Project("/home/user/first").Last("build")
+	assert.Nil(t, err)
+	assert.Equal(t, "first\n", string(log))
+}
+
+func TestStorePrune(t *testing.T) {
+	dir := t.TempDir()
+	store := New(dir)
+
+	// the logs of each task are pruned on their own
+	for i := 0; i < maxLogs+2; i++ {
+		hash := fmt.Sprintf("hash%d", i)
+		write(t, store, "build", hash, hash)
+		write(t, store, "sub/build", hash, hash)
+
+		mtime := time.Now().Add(time.Duration(i-maxLogs-2) * time.Minute)
+		assert.Nil(t, os.Chtimes(filepath.Join(dir, "build", hash+".log"), mtime, mtime))
+		assert.Nil(t, os.Chtimes(filepath.Join(dir, "sub", "build", hash+".log"), mtime, mtime))
+	}
+	write(t, store, "test", "abc", "test")
+
+	assert.Nil(t, store.Prune())
+
+	for _, taskname := range []string{"build", "sub/build"} {
+		_, err := store.Get(taskname, "hash0")
+		assert.ErrorIs(t, err, ErrLogDoesNotExist)
+		_, err = store.Get(taskname, "hash1")
+		assert.ErrorIs(t, err, ErrLogDoesNotExist)
+		_, err = store.Get(taskname, "hash2")
+		assert.Nil(t, err)
+
+		log, err := store.Last(taskname)
+		assert.Nil(t, err)
+		assert.Equal(t, fmt.Sprintf("hash%d", maxLogs+1), string(log))
+	}
+
+	_, err := store.Get("test", "abc")
+	assert.Nil(t, err)
+
+	// pruning an empty store
+	assert.Nil(t, New(filepath.Join(dir, "empty")).Prune())
+}
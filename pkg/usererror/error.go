@@ -17,10 +17,9 @@ type E struct {
 	// can be colored.
 	msg string
 
-	// summary of a underlying error.
-	// Could be the last 5 lines of a build error.
-	// For future use!
-	summary string // nolint:structcheck, unused
+	// summary of a underlying error shown below the message,
+	// e.g. the last lines of output of a failed command.
+	summary string
 }
 
 func (e *E) Error() string {
@@ -35,6 +34,16 @@ func (e *E) Msg() string {
 	return e.msg
 }
 
+func (e *E) Summary() string {
+	return e.summary
+}
+
+// WithSummary attaches a summary to the error.
+func (e *E) WithSummary(summary string) *E {
+	e.summary = summary
+	return e
+}
+
 func (e *E) Unwrap() error {
 	return e.err
 }
@@ -14,7 +14,8 @@ import (
 )
 
 var (
-	dir string
+	dir        string
+	storageDir string
 
 	cleanup func() error
 	b       *bob.B
@@ -22,7 +23,6 @@ var (
 
 var _ = BeforeSuite(func() {
 	var err error
-	var storageDir string
 	dir, storageDir, cleanup, err = setup.TestDirs("build")
 	Expect(err).NotTo(HaveOccurred())
 
@@ -2,9 +2,14 @@ package buildtest
 
 import (
 	"context"
+	"encoding/json"
+	"encoding/xml"
 	"errors"
+	"os"
+	"path/filepath"
 
 	"github.com/benchkram/bob/bob"
+	"github.com/benchkram/bob/bob/global"
 	"github.com/benchkram/bob/bob/playbook"
 	"github.com/benchkram/bob/pkg/file"
 
@@ -36,6 +41,66 @@ var _ = Describe("Test bob build", func() {
 			Expect(file.Exists("slowdone")).To(BeTrue(), "slowdone file should exist")
 		})
 
+		It("explains rebuild decisions in a dry run without executing tasks", func() {
+			ctx := context.Background()
+
+			decisions, err := b.DryRun(ctx, "slow", bob.BuildAlwaysTargetName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(decisions).To(HaveLen(2))
+
+			Expect(decisions[0].TaskName).To(Equal("slow"))
+			Expect(decisions[0].RebuildRequired).To(BeFalse())
+			Expect(decisions[0].Cause).To(BeEmpty())
+
+			Expect(decisions[1].TaskName).To(Equal(bob.BuildAlwaysTargetName))
+			Expect(decisions[1].RebuildRequired).To(BeTrue())
+			Expect(decisions[1].Cause).To(Equal(playbook.TaskForcedRebuild))
+		})
+
+		It("builds multiple tasks in a single playbook", func() {
+			ctx := context.Background()
+			Expect(b.Build(ctx, "multilinetouch", bob.BuildTargetwithdirsTargetName)).NotTo(HaveOccurred())
+
+			Expect(file.Exists("multilinefile5")).To(BeTrue(), "multilinefile5 file should exist")
+			Expect(file.Exists(".bbuild/dirone/dirtwo/filetwo")).To(BeTrue(), "target of second task should exist")
+
+			aggregate, err := b.Aggregate()
+			Expect(err).NotTo(HaveOccurred())
+			single, err := aggregate.Playbook(bob.BuildAllTargetName)
+			Expect(err).NotTo(HaveOccurred())
+			multi, err := aggregate.PlaybookMultiRoot([]string{bob.BuildAllTargetName, "second-level/build2"})
+			Expect(err).NotTo(HaveOccurred())
+
+			// second-level/build2 is already a dependency of `all`,
+			// common dependencies are only contained once.
+			Expect(multi.Tasks).To(HaveLen(len(single.Tasks)))
+		})
+
+		It("explains which inputs changed since the last successful build", func() {
+			ctx := context.Background()
+			Expect(b.Build(ctx, global.DefaultBuildTask)).NotTo(HaveOccurred())
+
+			explanation, err := b.Why(global.DefaultBuildTask)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(explanation.UpToDate).To(BeTrue())
+
+			f, err := os.OpenFile("main1.go", os.O_APPEND|os.O_WRONLY, 0644)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = f.WriteString("\n// changed\n")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(f.Close()).NotTo(HaveOccurred())
+
+			explanation, err = b.Why(global.DefaultBuildTask)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(explanation.UpToDate).To(BeFalse())
+			Expect(explanation.Previous).NotTo(BeNil())
+			Expect(explanation.Diff.ModifiedFiles).To(HaveLen(1))
+			Expect(filepath.Base(explanation.Diff.ModifiedFiles[0])).To(Equal("main1.go"))
+			Expect(explanation.Diff.AddedFiles).To(BeEmpty())
+			Expect(explanation.Diff.RemovedFiles).To(BeEmpty())
+			Expect(explanation.Diff.DescriptionChanged).To(BeFalse())
+		})
+
 		It("expect rebuild always true without change for always rebuild task", func() {
 
 			ctx := context.Background()
@@ -55,5 +120,162 @@ var _ = Describe("Test bob build", func() {
 			Expect(rebuildRequired).To(BeTrue())
 			Expect(rebuildCause).To(Equal(playbook.TaskForcedRebuild))
 		})
+
+		It("emits an event for each task state change", func() {
+			ctx := context.Background()
+
+			var events []playbook.Event
+			aggregate, err := b.Aggregate()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(b.Nix().BuildNixDependenciesInPipeline(aggregate, bob.BuildAlwaysTargetName)).NotTo(HaveOccurred())
+			pb, err := aggregate.Playbook(bob.BuildAlwaysTargetName, playbook.WithSubscriber(func(e playbook.Event) {
+				events = append(events, e)
+			}))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pb.Build(ctx)).NotTo(HaveOccurred())
+
+			var types []playbook.EventType
+			for _, e := range events {
+				Expect(e.Task).To(Equal(bob.BuildAlwaysTargetName))
+				types = append(types, e.Type)
+			}
+			Expect(types).To(Equal([]playbook.EventType{
+				playbook.EventQueued,
+				playbook.EventRunning,
+				playbook.EventCompleted,
+			}))
+
+			completed := events[2]
+			Expect(completed.InputHash).NotTo(BeEmpty())
+			Expect(completed.RebuildCause).To(Equal(playbook.TaskForcedRebuild))
+			Expect(completed.Start).NotTo(BeNil())
+			Expect(completed.End).NotTo(BeNil())
+		})
+
+		It("writes a trace and a junit report", func() {
+			ctx := context.Background()
+
+			tracePath := filepath.Join(dir, "trace.json")
+			junitPath := filepath.Join(dir, "junit.xml")
+
+			aggregate, err := b.Aggregate()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(b.Nix().BuildNixDependenciesInPipeline(aggregate, "slow", bob.BuildAlwaysTargetName)).NotTo(HaveOccurred())
+			pb, err := aggregate.PlaybookMultiRoot(
+				[]string{"slow", bob.BuildAlwaysTargetName},
+				playbook.WithTraceReport(tracePath),
+				playbook.WithJUnitReport(junitPath),
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pb.Build(ctx)).NotTo(HaveOccurred())
+
+			traceFile, err := os.ReadFile(tracePath)
+			Expect(err).NotTo(HaveOccurred())
+			var trace struct {
+				TraceEvents []struct {
+					Name  string `json:"name"`
+					Phase string `json:"ph"`
+					Dur   int64  `json:"dur"`
+					TID   int    `json:"tid"`
+				} `json:"traceEvents"`
+			}
+			Expect(json.Unmarshal(traceFile, &trace)).NotTo(HaveOccurred())
+
+			durations := make(map[string]int64)
+			for _, e := range trace.TraceEvents {
+				if e.Phase == "X" {
+					Expect(e.TID).To(BeNumerically(">", 0))
+					durations[e.Name] = e.Dur
+				}
+			}
+			Expect(durations).To(HaveLen(2))
+			Expect(durations["slow"]).To(BeZero(), "cached task should have zero duration")
+			Expect(durations[bob.BuildAlwaysTargetName]).To(BeNumerically(">", 0))
+
+			junitFile, err := os.ReadFile(junitPath)
+			Expect(err).NotTo(HaveOccurred())
+			var junit struct {
+				Testsuites []struct {
+					Tests     int `xml:"tests,attr"`
+					Skipped   int `xml:"skipped,attr"`
+					Failures  int `xml:"failures,attr"`
+					Testcases []struct {
+						Name    string    `xml:"name,attr"`
+						Time    string    `xml:"time,attr"`
+						Skipped *struct{} `xml:"skipped"`
+					} `xml:"testcase"`
+				} `xml:"testsuite"`
+			}
+			Expect(xml.Unmarshal(junitFile, &junit)).NotTo(HaveOccurred())
+			Expect(junit.Testsuites).To(HaveLen(1))
+
+			suite := junit.Testsuites[0]
+			Expect(suite.Tests).To(Equal(2))
+			Expect(suite.Skipped).To(Equal(1))
+			Expect(suite.Failures).To(Equal(0))
+			for _, tc := range suite.Testcases {
+				if tc.Name == "slow" {
+					Expect(tc.Skipped).NotTo(BeNil())
+					Expect(tc.Time).To(Equal("0.000"))
+				} else {
+					Expect(tc.Skipped).To(BeNil())
+				}
+			}
+		})
+
+		It("builds with grouped output", func() {
+			ctx := context.Background()
+
+			aggregate, err := b.Aggregate()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(b.Nix().BuildNixDependenciesInPipeline(aggregate, bob.BuildAlwaysTargetName)).NotTo(HaveOccurred())
+			pb, err := aggregate.PlaybookMultiRoot(
+				[]string{bob.BuildAlwaysTargetName},
+				playbook.WithOutputMode(playbook.OutputGrouped),
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pb.Build(ctx)).NotTo(HaveOccurred())
+
+			status, err := pb.TaskStatus(bob.BuildAlwaysTargetName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status.State()).To(Equal(playbook.StateCompleted))
+		})
+
+		It("records each build in the history", func() {
+			ctx := context.Background()
+
+			before, err := b.History()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(b.Build(ctx, "slow")).NotTo(HaveOccurred())
+
+			runs, err := b.History()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(runs).To(HaveLen(len(before) + 1))
+
+			last := runs[len(runs)-1]
+			Expect(last.Tasks).To(Equal([]string{"slow"}))
+			Expect(last.Failed()).To(BeFalse())
+			Expect(last.TaskRecords).To(HaveLen(1))
+			Expect(last.TaskRecords[0].Name).To(Equal("slow"))
+			Expect(last.TaskRecords[0].State).To(Equal("cached"))
+			Expect(last.TaskRecords[0].InputHash).NotTo(BeEmpty())
+			Expect(last.Cached()).To(Equal(1))
+
+			run, err := b.HistoryRun(last.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(run.TaskRecords).To(Equal(last.TaskRecords))
+		})
+
+		It("records the execution time of the tasks run by a build", func() {
+			ctx := context.Background()
+
+			Expect(b.Build(ctx, bob.BuildAlwaysTargetName)).NotTo(HaveOccurred())
+
+			durations, err := bob.DurationStore(storageDir).Durations(dir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(durations).To(HaveKey(bob.BuildAlwaysTargetName))
+			Expect(durations[bob.BuildAlwaysTargetName]).To(BeNumerically(">", 0))
+		})
 	})
 })
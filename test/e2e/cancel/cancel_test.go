@@ -0,0 +1,43 @@
+package canceltest
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/benchkram/bob/bob"
+	"github.com/benchkram/bob/bob/playbook"
+	"github.com/benchkram/bob/pkg/file"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Testing cancellation of a build", func() {
+	It("should cancel running and pending tasks and roll back partial targets", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		states := make(map[string]playbook.EventType)
+		b, err := suite.BobSetup(bob.WithSubscriber(func(e playbook.Event) {
+			states[e.Task] = e.Type
+			if e.Type == playbook.EventRunning && e.Task == "slow" {
+				// give the task time to partially write its target
+				time.AfterFunc(500*time.Millisecond, cancel)
+			}
+		}))
+		Expect(err).NotTo(HaveOccurred())
+
+		start := time.Now()
+		err = b.Build(ctx, "after")
+		Expect(err).To(HaveOccurred())
+		Expect(errors.Is(err, context.Canceled)).To(BeTrue())
+		Expect(time.Since(start)).To(BeNumerically("<", 5*time.Second))
+
+		Expect(states["slow"]).To(Equal(playbook.EventCanceled))
+		Expect(states["after"]).To(Equal(playbook.EventCanceled))
+
+		Expect(file.Exists("slow-result")).To(BeFalse(), "partial target should have been removed")
+		Expect(file.Exists("after-result")).To(BeFalse())
+	})
+})
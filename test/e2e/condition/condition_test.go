@@ -0,0 +1,76 @@
+package conditiontest
+
+import (
+	"context"
+	"os"
+
+	"github.com/benchkram/bob/bob"
+	"github.com/benchkram/bob/bob/playbook"
+	"github.com/benchkram/bob/pkg/file"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Testing conditional tasks", func() {
+	ctx := context.Background()
+
+	It("should skip tasks with a false condition and still build their dependents", func() {
+		var skipped []string
+		b, err := suite.BobSetup(bob.WithSubscriber(func(e playbook.Event) {
+			if e.Type == playbook.EventSkipped {
+				skipped = append(skipped, e.Task)
+			}
+		}))
+		Expect(err).NotTo(HaveOccurred())
+
+		err = b.Build(ctx, "build")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(skipped).To(Equal([]string{"optional"}))
+		Expect(file.Exists("optional-result")).To(BeFalse(), "optional should have been skipped")
+		Expect(file.Exists("docs-result")).To(BeTrue(), "docs should have been built")
+		Expect(file.Exists("build-result")).To(BeTrue(), "build should have been built")
+	})
+
+	It("should evaluate conditions against environment variables", func() {
+		err := os.Remove("docs-result")
+		Expect(err).NotTo(HaveOccurred())
+
+		b, err := suite.BobSetup(bob.WithEnvVariables([]string{"SKIP_DOCS=true"}))
+		Expect(err).NotTo(HaveOccurred())
+
+		decisions, err := b.DryRun(ctx, "build")
+		Expect(err).NotTo(HaveOccurred())
+
+		skipped := map[string]bool{}
+		for _, d := range decisions {
+			skipped[d.TaskName] = d.Skipped
+		}
+		Expect(skipped).To(Equal(map[string]bool{
+			"optional": true,
+			"docs":     true,
+			"build":    false,
+		}))
+
+		err = b.Build(ctx, "build")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(file.Exists("docs-result")).To(BeFalse(), "docs should have been skipped")
+	})
+
+	It("should not build nix dependencies of skipped tasks", func() {
+		b, err := suite.BobSetup()
+		Expect(err).NotTo(HaveOccurred())
+
+		ag, err := b.Aggregate()
+		Expect(err).NotTo(HaveOccurred())
+
+		err = b.Nix().BuildNixDependenciesInPipeline(ag, "build")
+		Expect(err).NotTo(HaveOccurred())
+
+		optional := ag.BTasks["optional"]
+		Expect(optional.StorePaths()).To(BeEmpty())
+		build := ag.BTasks["build"]
+		Expect(build.StorePaths()).NotTo(BeEmpty())
+	})
+})
@@ -0,0 +1,40 @@
+package conditiontest
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/benchkram/bob/test/setup/suitesetup"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// suite is the temporary bob project the tests run in.
+var suite *suitesetup.Suite
+
+var _ = BeforeSuite(func() {
+	var err error
+	suite, err = suitesetup.New("condition", "./with_conditional_tasks")
+	Expect(err).NotTo(HaveOccurred())
+})
+
+var _ = AfterSuite(func() {
+	err := suite.Cleanup()
+	Expect(err).NotTo(HaveOccurred())
+})
+
+func TestCondition(t *testing.T) {
+	_, err := exec.LookPath("nix")
+	if err != nil {
+		// Allow to skip tests only locally.
+		// CI is always set to true on GitHub actions.
+		// https://docs.github.com/en/actions/learn-github-actions/environment-variables#default-environment-variables
+		if os.Getenv("CI") != "true" {
+			t.Skip("Test skipped because nix is not installed on your system")
+		}
+	}
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "condition suite")
+}
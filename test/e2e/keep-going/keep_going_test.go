@@ -0,0 +1,75 @@
+package keepgoingtest
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/benchkram/bob/bob"
+	"github.com/benchkram/bob/bob/playbook"
+	"github.com/benchkram/bob/pkg/file"
+	"github.com/benchkram/bob/pkg/usererror"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Testing keep-going mode", func() {
+	ctx := context.Background()
+
+	When("keep-going is disabled", func() {
+		It("should stop on the first failure", func() {
+			b, err := suite.BobSetup(bob.WithMaxParallel(1))
+			Expect(err).NotTo(HaveOccurred())
+
+			err = b.Build(ctx, "build")
+			Expect(err).To(HaveOccurred())
+
+			var failedTasks *playbook.FailedTasksError
+			Expect(errors.As(err, &failedTasks)).To(BeFalse())
+		})
+
+		It("should cleanup the targets", func() {
+			_ = os.Remove("binary")
+		})
+	})
+
+	When("keep-going is enabled", func() {
+		It("should build independent tasks and report all failures", func() {
+			b, err := suite.BobSetup(bob.WithKeepGoing(true), bob.WithMaxParallel(4))
+			Expect(err).NotTo(HaveOccurred())
+
+			err = b.Build(ctx, "build")
+			Expect(err).To(HaveOccurred())
+
+			var failedTasks *playbook.FailedTasksError
+			Expect(errors.As(err, &failedTasks)).To(BeTrue())
+			Expect(failedTasks.Errs).To(HaveLen(2))
+
+			// the summary shows the failing command of each task
+			var uerr *usererror.E
+			Expect(errors.As(err, &uerr)).To(BeTrue())
+			Expect(uerr.Summary()).To(ContainSubstring("command:     exit 1"))
+			Expect(uerr.Summary()).To(ContainSubstring("command:     exit 2"))
+
+			Expect(file.Exists("binary")).To(BeTrue(), "independent task should have finished")
+			Expect(file.Exists("test-result")).To(BeFalse(), "task with failed dependency should not run")
+		})
+
+		It("should have stored the buildinfo of the successful task", func() {
+			b, err := suite.BobSetup()
+			Expect(err).NotTo(HaveOccurred())
+
+			aggregate, err := b.Aggregate()
+			Expect(err).NotTo(HaveOccurred())
+			err = b.Nix().BuildNixDependenciesInPipeline(aggregate, "compile")
+			Expect(err).NotTo(HaveOccurred())
+			pb, err := aggregate.Playbook("compile")
+			Expect(err).NotTo(HaveOccurred())
+
+			rebuildRequired, _, err := pb.TaskNeedsRebuild("compile")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rebuildRequired).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,70 @@
+package matrixtest
+
+import (
+	"context"
+	"os"
+
+	"github.com/benchkram/bob/bob"
+	"github.com/benchkram/bob/bob/playbook"
+	"github.com/benchkram/bob/pkg/file"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Testing matrix tasks", func() {
+	ctx := context.Background()
+
+	It("should build all instances of a matrix a task depends on", func() {
+		b, err := suite.BobSetup()
+		Expect(err).NotTo(HaveOccurred())
+
+		err = b.Build(ctx, "package")
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, target := range []string{"app-linux-amd64", "app-linux-arm64", "app-darwin-amd64", "app-darwin-arm64"} {
+			Expect(file.Exists(target)).To(BeTrue(), target+" should have been built")
+		}
+
+		content, err := os.ReadFile("app-darwin-arm64")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(Equal("darwin/arm64\n"))
+	})
+
+	It("should cache each instance on its own", func() {
+		err := os.Remove("app-linux-arm64")
+		Expect(err).NotTo(HaveOccurred())
+
+		b, err := suite.BobSetup()
+		Expect(err).NotTo(HaveOccurred())
+
+		decisions, err := b.DryRun(ctx, "build")
+		Expect(err).NotTo(HaveOccurred())
+
+		rebuild := map[string]bool{}
+		for _, d := range decisions {
+			rebuild[d.TaskName] = d.Cause != ""
+		}
+		Expect(rebuild).To(Equal(map[string]bool{
+			"build[linux,amd64]":  false,
+			"build[linux,arm64]":  true,
+			"build[darwin,amd64]": false,
+			"build[darwin,arm64]": false,
+		}))
+	})
+
+	It("should build a single instance", func() {
+		var processed []string
+		b, err := suite.BobSetup(bob.WithSubscriber(func(e playbook.Event) {
+			if e.Type == playbook.EventCompleted || e.Type == playbook.EventNoRebuildRequired {
+				processed = append(processed, e.Task)
+			}
+		}))
+		Expect(err).NotTo(HaveOccurred())
+
+		err = b.Build(ctx, "build[linux,arm64]")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(processed).To(Equal([]string{"build[linux,arm64]"}))
+		Expect(file.Exists("app-linux-arm64")).To(BeTrue())
+	})
+})
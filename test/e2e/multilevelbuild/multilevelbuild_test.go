@@ -13,6 +13,7 @@ import (
 	"github.com/benchkram/bob/bob"
 	"github.com/benchkram/bob/bob/playbook"
 	"github.com/benchkram/bob/pkg/file"
+	"github.com/benchkram/bob/pkg/taskgraph"
 	"github.com/benchkram/errz"
 
 	. "github.com/onsi/ginkgo"
@@ -42,6 +43,29 @@ var _ = Describe("Test bob multilevel build", func() {
 			cancel()
 		})
 
+		It("exports the task graph across bobfiles", func() {
+			g, err := b.Graph(context.Background(), true, bob.BuildAllTargetName)
+			Expect(err).NotTo(HaveOccurred())
+
+			build2 := filepath.Join(bob.SecondLevelDir, "build2")
+			build3 := filepath.Join(bob.SecondLevelDir, bob.ThirdLevelDir, "build3")
+			print := filepath.Join(bob.SecondLevelDir, bob.ThirdLevelDir, "print")
+
+			var names []string
+			for _, n := range g.Nodes {
+				names = append(names, n.Name)
+				Expect(n.State).To(Equal(taskgraph.StateCached), fmt.Sprintf("task %q should be cached", n.Name))
+			}
+			Expect(names).To(ContainElements(bob.BuildAllTargetName, build2, build3, print))
+			Expect(g.Edges).To(ContainElements(
+				taskgraph.Edge{From: bob.BuildAllTargetName, To: build2},
+				taskgraph.Edge{From: bob.BuildAllTargetName, To: print},
+				taskgraph.Edge{From: build2, To: build3},
+			))
+			Expect(g.Nodes[0].Inputs).To(Equal([]string{"./main1.go"}))
+			Expect(g.Nodes[0].Targets).To(Equal([]string{"run"}))
+		})
+
 		binaries := []binaryOutputFixture{
 			{
 				path:   filepath.Join(dir, "run"),
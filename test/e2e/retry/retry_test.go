@@ -0,0 +1,43 @@
+package retrytest
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/benchkram/bob/bobtask"
+	"github.com/benchkram/bob/pkg/file"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Testing timeout and retry policy", func() {
+	ctx := context.Background()
+
+	It("should retry a flaky task till it succeeds", func() {
+		b, err := suite.BobSetup()
+		Expect(err).NotTo(HaveOccurred())
+
+		err = b.Build(ctx, "flaky")
+		Expect(err).NotTo(HaveOccurred())
+
+		attempts, err := os.ReadFile("attempts")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(strings.Count(string(attempts), "attempt")).To(Equal(2), "task should have succeeded on the first retry")
+		Expect(file.Exists("flaky-result")).To(BeTrue(), "retry should have succeeded")
+	})
+
+	It("should cancel a task exceeding its timeout", func() {
+		b, err := suite.BobSetup()
+		Expect(err).NotTo(HaveOccurred())
+
+		start := time.Now()
+		err = b.Build(ctx, "hanging")
+		Expect(err).To(HaveOccurred())
+		Expect(errors.Is(err, bobtask.ErrTaskTimeout)).To(BeTrue())
+		Expect(time.Since(start)).To(BeNumerically("<", 5*time.Second))
+	})
+})
@@ -0,0 +1,111 @@
+package suitesetup
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/benchkram/bob/bob"
+	"github.com/benchkram/bob/bob/bobfile"
+	"github.com/benchkram/bob/pkg/buildinfostore"
+	"github.com/benchkram/bob/pkg/nix"
+	"github.com/benchkram/bob/pkg/store"
+	"github.com/benchkram/bob/test/setup"
+	"github.com/benchkram/errz"
+)
+
+// Suite is a temporary bob project used by a test suite.
+// It uses its own stores to avoid interfeering with the users cache.
+type Suite struct {
+	// Dir is the basic test directory
+	// in which the test is executed.
+	Dir string
+
+	artifactStore  store.Store
+	buildInfoStore buildinfostore.Store
+
+	// cleanup removes the test directories from the system.
+	cleanup func() error
+
+	// tmpFiles tracks temporarily created files
+	// to be cleaned up at the end.
+	tmpFiles []string
+}
+
+// New copies the bobfile from fixtureDir into a fresh test directory
+// and changes into it. Call Cleanup() at the end of the suite.
+func New(testname, fixtureDir string) (_ *Suite, err error) {
+	defer errz.Recover(&err)
+
+	abs, err := filepath.Abs(fixtureDir)
+	errz.Fatal(err)
+	bf, err := bobfile.BobfileRead(abs)
+	errz.Fatal(err)
+
+	s := &Suite{}
+
+	var storageDir string
+	s.Dir, storageDir, s.cleanup, err = setup.TestDirs(testname)
+	errz.Fatal(err)
+
+	s.artifactStore, err = bob.Filestore(storageDir)
+	errz.Fatal(err)
+	s.buildInfoStore, err = bob.BuildinfoStore(storageDir)
+	errz.Fatal(err)
+
+	err = os.Chdir(s.Dir)
+	errz.Fatal(err)
+
+	err = bf.BobfileSave(s.Dir, "bob.yaml")
+	errz.Fatal(err)
+
+	return s, nil
+}
+
+// BobSetup returns a bob instance working on the suite's directory and stores.
+func (s *Suite) BobSetup(opts ...bob.Option) (_ *bob.B, err error) {
+	defer errz.Recover(&err)
+
+	nixBuilder, err := s.nixBuilder()
+	errz.Fatal(err)
+
+	static := []bob.Option{
+		bob.WithDir(s.Dir),
+		bob.WithNixBuilder(nixBuilder),
+		bob.WithFilestore(s.artifactStore),
+		bob.WithBuildinfoStore(s.buildInfoStore),
+	}
+	static = append(static, opts...)
+	return bob.Bob(
+		static...,
+	)
+}
+
+// Cleanup removes all files created by the suite.
+func (s *Suite) Cleanup() error {
+	for _, file := range s.tmpFiles {
+		err := os.Remove(file)
+		if err != nil {
+			return err
+		}
+	}
+	return s.cleanup()
+}
+
+func (s *Suite) nixBuilder() (*bob.NixBuilder, error) {
+	file, err := ioutil.TempFile("", ".nix_cache*")
+	if err != nil {
+		return nil, err
+	}
+	name := file.Name()
+	file.Close()
+
+	s.tmpFiles = append(s.tmpFiles, name)
+
+	cache, err := nix.NewCacheStore(nix.WithPath(name))
+	if err != nil {
+		return nil, err
+	}
+
+	return bob.NewNixBuilder(bob.WithCache(cache)), nil
+}